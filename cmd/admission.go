@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/admission"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/metrics"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// admittedTTL bounds how long an admitted task without a deadline waits for
+// HandleTask before it is forgotten.
+const admittedTTL = time.Hour
+
+// defaultPolicyReloadInterval is how often the policy file is checked for
+// changes.
+const defaultPolicyReloadInterval = 10 * time.Second
+
+// admitTask checks that t's handler is enabled, then applies the fee check,
+// the SLA check and the creator policy to t, and records what HandleTask needs
+// to know about it.
+func (tw *TaskWorker) admitTask(t *performerV1.TaskRequest, task *mailbox.Task) error {
+	// Admission is about wall-clock time, not the deterministic task clock.
+	now := time.Now()
+	admitted := admittedTask{expires: now.Add(admittedTTL)}
+
+	if err := tw.checkHandler(t, defaultHandler); err != nil {
+		return err
+	}
+	// Underpaid and late tasks are rejected before the creator policy so that
+	// they don't use up quota.
+	if err := tw.checkFee(t, task); err != nil {
+		return err
+	}
+
+	deadline, ok := admission.TaskDeadline(task)
+	if !ok {
+		deadline, ok = tw.payloadDeadline(t)
+	}
+	if ok {
+		remaining, err := tw.sla.Admit(defaultHandler, deadline, now)
+		if err != nil {
+			metrics.TasksLate.Inc(1)
+			tw.logger.Warn("Rejecting task that cannot meet its deadline", zap.Binary("taskId", t.GetTaskId()), zap.Error(err))
+			return err
+		}
+		admitted.deadline = now.Add(remaining)
+		admitted.expires = admitted.deadline
+	}
+
+	if err := tw.applyPolicy(t, task, now); err != nil {
+		return err
+	}
+	if task != nil && tw.policy != nil {
+		admitted.creator = &task.Creator
+	}
+
+	tw.admitted.put(t.GetTaskId(), admitted, now)
+	return nil
+}
+
+// checkFee rejects t if it doesn't pay the handler's minimum fee. Tasks
+// whose fee can't be read from the TaskMailbox are rejected.
+func (tw *TaskWorker) checkFee(t *performerV1.TaskRequest, task *mailbox.Task) error {
+	if tw.feesErr != nil {
+		return fmt.Errorf("fee checks are unavailable: %w", tw.feesErr)
+	}
+	if tw.fees == nil {
+		return nil
+	}
+
+	var err error
+	if task == nil || task.TaskStatus() == mailbox.TaskStatusNone {
+		err = &admission.FeeError{Handler: defaultHandler, Reason: "task fee could not be read from the TaskMailbox"}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), mailboxTimeout)
+		defer cancel()
+		err = tw.fees.Check(ctx, defaultHandler, task)
+	}
+	if err != nil {
+		metrics.TasksUnderpaid.Inc(1)
+		tw.logger.Warn("Rejecting task over its fee", zap.Binary("taskId", t.GetTaskId()), zap.Error(err))
+	}
+	return err
+}
+
+// applyPolicy checks the task's creator against the creator policy, if one is
+// configured, and writes every decision to the audit log. Tasks whose creator
+// can't be resolved are rejected.
+func (tw *TaskWorker) applyPolicy(t *performerV1.TaskRequest, task *mailbox.Task, now time.Time) error {
+	if tw.policyErr != nil {
+		return &admission.PolicyError{Rule: admission.RuleUnknownCreator, Reason: "policy unavailable: " + tw.policyErr.Error()}
+	}
+	if tw.policy == nil {
+		return nil
+	}
+
+	var decision admission.Decision
+	var err error
+	if task == nil || task.TaskStatus() == mailbox.TaskStatusNone {
+		reason := "task creator could not be resolved from the TaskMailbox"
+		decision = admission.Decision{Rule: admission.RuleUnknownCreator, Reason: reason}
+		err = &admission.PolicyError{Rule: admission.RuleUnknownCreator, Reason: reason}
+	} else {
+		decision, err = tw.policy.Admit(task.Creator, now)
+	}
+
+	tw.audit.Info("Policy decision",
+		zap.Binary("taskId", t.GetTaskId()),
+		zap.String("creator", decision.Creator.Hex()),
+		zap.Bool("allowed", decision.Allowed),
+		zap.String("rule", decision.Rule),
+		zap.String("reason", decision.Reason),
+	)
+	if err != nil {
+		metrics.TasksDenied.Inc(1)
+	}
+	return err
+}
+
+// recordCompute charges the time spent handling a task to its creator.
+func (tw *TaskWorker) recordCompute(admitted admittedTask, start time.Time) {
+	if tw.policy == nil || admitted.creator == nil {
+		return
+	}
+	now := time.Now()
+	tw.policy.RecordCompute(*admitted.creator, now.Sub(start), now)
+}
+
+// watchPolicy reloads the policy file when it changes, until ctx is done.
+func (tw *TaskWorker) watchPolicy(ctx context.Context, interval time.Duration) {
+	if tw.policy == nil || tw.policyFile == "" {
+		return
+	}
+	tw.policy.WatchFile(ctx, tw.policyFile, interval,
+		func() { tw.audit.Info("Reloaded policy", zap.String("file", tw.policyFile)) },
+		func(err error) {
+			tw.logger.Error("Failed to reload policy, keeping the current rules", zap.String("file", tw.policyFile), zap.Error(err))
+		},
+	)
+}
+
+// admittedTask is what ValidateTask hands over to HandleTask.
+type admittedTask struct {
+	// deadline is when the handler must be done; zero if unknown.
+	deadline time.Time
+	// creator is the task's creator when the policy needs its compute usage.
+	creator *common.Address
+	expires time.Time
+}
+
+// admittedTasks holds admitted tasks until they are handled.
+type admittedTasks struct {
+	mu    sync.Mutex
+	tasks map[string]admittedTask
+}
+
+func newAdmittedTasks() *admittedTasks {
+	return &admittedTasks{tasks: make(map[string]admittedTask)}
+}
+
+// put records an admitted task and drops expired ones, which belong to tasks
+// that were never handled.
+func (a *admittedTasks) put(taskId []byte, task admittedTask, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for id, t := range a.tasks {
+		if t.expires.Before(now) {
+			delete(a.tasks, id)
+		}
+	}
+	a.tasks[hex.EncodeToString(taskId)] = task
+}
+
+// take returns and forgets the admitted task for taskId.
+func (a *admittedTasks) take(taskId []byte) (admittedTask, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := hex.EncodeToString(taskId)
+	task, ok := a.tasks[key]
+	delete(a.tasks, key)
+	return task, ok
+}
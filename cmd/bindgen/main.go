@@ -0,0 +1,51 @@
+// Command bindgen generates contracts/bindings from forge build artifacts.
+//
+//	go run ./cmd/bindgen          # regenerate the bindings
+//	go run ./cmd/bindgen -check   # fail if the bindings are out of date
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/bindgen"
+)
+
+func main() {
+	root := flag.String("root", ".", "Project root containing go.mod")
+	artifacts := flag.String("artifacts", "", "Forge output directory (default: <root>/.devkit/contracts/out)")
+	check := flag.Bool("check", false, "Report bindings that differ from the artifacts instead of writing them")
+	flag.Parse()
+
+	cfg := &bindgen.Config{Root: *root, ArtifactsDir: *artifacts}
+
+	files, err := bindgen.Generate(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bindgen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *check {
+		drift, err := bindgen.Check(cfg, files)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bindgen: %v\n", err)
+			os.Exit(1)
+		}
+		if len(drift) > 0 {
+			fmt.Fprintln(os.Stderr, "Contract bindings are out of date; run `make bindings`:")
+			for _, d := range drift {
+				fmt.Fprintf(os.Stderr, "  contracts/bindings/%s\n", d)
+			}
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stderr, "Contract bindings are up to date.")
+		return
+	}
+
+	if err := bindgen.Write(cfg, files); err != nil {
+		fmt.Fprintf(os.Stderr, "bindgen: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Generated %d files in contracts/bindings\n", len(files))
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/deployments"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// chainFlags are the flags shared by subcommands that send transactions.
+// Signer flags override the SIGNER_* and GAS_* environment variables read by
+// pkg/signer.
+type chainFlags struct {
+	rpcUrl               *string
+	signerType           *string
+	keystorePath         *string
+	keystorePasswordFile *string
+	remoteUrl            *string
+	signerAddress        *string
+	tipCapGwei           *string
+	maxFeeCapGwei        *string
+
+	// The signer is built once, so that a keystore is only unlocked once per
+	// command.
+	signerOnce sync.Once
+	s          signer.Signer
+	signerErr  error
+}
+
+func addChainFlags(fs *flag.FlagSet, rpcUrlEnv string) *chainFlags {
+	return &chainFlags{
+		rpcUrl:               fs.String("rpc-url", os.Getenv(rpcUrlEnv), "RPC URL (default $"+rpcUrlEnv+")"),
+		signerType:           fs.String("signer", "", "Signer backend: keystore, env or remote (default $SIGNER_TYPE)"),
+		keystorePath:         fs.String("keystore", "", "Encrypted JSON keystore (default $SIGNER_KEYSTORE_PATH)"),
+		keystorePasswordFile: fs.String("keystore-password-file", "", "File containing the keystore password (default $SIGNER_KEYSTORE_PASSWORD_FILE)"),
+		remoteUrl:            fs.String("remote-signer-url", "", "Remote signer URL (default $SIGNER_REMOTE_URL)"),
+		signerAddress:        fs.String("signer-address", "", "Account to sign with on the remote signer (default $SIGNER_ADDRESS)"),
+		tipCapGwei:           fs.String("tip-cap-gwei", "", "Fixed priority fee in gwei (default $GAS_TIP_CAP_GWEI)"),
+		maxFeeCapGwei:        fs.String("max-fee-cap-gwei", "", "Refuse to send above this fee cap in gwei (default $GAS_MAX_FEE_CAP_GWEI)"),
+	}
+}
+
+func (f *chainFlags) dial(ctx context.Context) (*ethclient.Client, error) {
+	if *f.rpcUrl == "" {
+		return nil, fmt.Errorf("--rpc-url is required")
+	}
+	client, err := ethclient.DialContext(ctx, *f.rpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", *f.rpcUrl, err)
+	}
+	return client, nil
+}
+
+// signer builds the signer from the environment and the signer flags. Later
+// calls return the same signer.
+func (f *chainFlags) signer() (signer.Signer, error) {
+	f.signerOnce.Do(func() {
+		f.s, f.signerErr = f.newSigner()
+	})
+	return f.s, f.signerErr
+}
+
+func (f *chainFlags) newSigner() (signer.Signer, error) {
+	cfg, err := signer.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	override(&cfg.Type, *f.signerType)
+	override(&cfg.KeystorePath, *f.keystorePath)
+	override(&cfg.RemoteURL, *f.remoteUrl)
+	override(&cfg.Address, *f.signerAddress)
+	if *f.keystorePasswordFile != "" {
+		password, err := os.ReadFile(*f.keystorePasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --keystore-password-file: %w", err)
+		}
+		cfg.KeystorePassword = strings.TrimRight(string(password), "\r\n")
+	}
+	return signer.New(cfg)
+}
+
+func (f *chainFlags) transactor(ctx context.Context, client *ethclient.Client) (*signer.Transactor, error) {
+	s, err := f.signer()
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := signer.GasPolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if *f.tipCapGwei != "" {
+		if policy.TipCap, err = signer.ParseGwei(*f.tipCapGwei); err != nil {
+			return nil, fmt.Errorf("invalid --tip-cap-gwei: %w", err)
+		}
+	}
+	if *f.maxFeeCapGwei != "" {
+		if policy.MaxFeeCap, err = signer.ParseGwei(*f.maxFeeCapGwei); err != nil {
+			return nil, fmt.Errorf("invalid --max-fee-cap-gwei: %w", err)
+		}
+	}
+
+	return signer.NewTransactor(ctx, s, client, policy)
+}
+
+func override(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// registrarAddress returns the TaskAVSRegistrar address from flag, or from
+// the contract store if flag is empty.
+func registrarAddress(flag string) (common.Address, error) {
+	if flag != "" {
+		if !common.IsHexAddress(flag) {
+			return common.Address{}, fmt.Errorf("invalid registrar address %q", flag)
+		}
+		return common.HexToAddress(flag), nil
+	}
+
+	cfg, err := deployments.ConfigFromEnv()
+	if err != nil {
+		return common.Address{}, err
+	}
+	store, err := loadContractStore(cfg)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("no --registrar given and the contract store is unavailable: %w", err)
+	}
+	addr, err := store.GetTaskAVSRegistrar()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("no --registrar given and TaskAVSRegistrar is not in the contract store: %w", err)
+	}
+	return addr, nil
+}
+
+// contractAddress returns the address in flag, the value of the --flagName
+// flag, or the contract store's address for name if flag is empty.
+func contractAddress(flagName string, flag string, name string) (common.Address, error) {
+	if flag != "" {
+		if !common.IsHexAddress(flag) {
+			return common.Address{}, fmt.Errorf("invalid --%s address %q", flagName, flag)
+		}
+		return common.HexToAddress(flag), nil
+	}
+
+	cfg, err := deployments.ConfigFromEnv()
+	if err != nil {
+		return common.Address{}, err
+	}
+	store, err := loadContractStore(cfg)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("no --%s given and the contract store is unavailable: %w", flagName, err)
+	}
+	addr, err := store.GetContract(name)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("no --%s given and %s is not in the contract store: %w", flagName, name, err)
+	}
+	return addr, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func Test_KeystorePasswordFileOverridesEnv(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	account, err := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP).ImportECDSA(key, "from-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SIGNER_TYPE", "")
+	t.Setenv("SIGNER_KEYSTORE_PASSWORD", "from-env")
+	t.Setenv("SIGNER_KEYSTORE_PASSWORD_FILE", "")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	chain := addChainFlags(fs, "L1_RPC_URL")
+	if err := fs.Parse([]string{"--keystore", account.URL.Path, "--keystore-password-file", passwordFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := chain.signer()
+	if err != nil {
+		t.Fatalf("expected the flag's password to unlock the keystore, got %v", err)
+	}
+	if s.Address() != account.Address {
+		t.Errorf("signer address %s, want %s", s.Address(), account.Address)
+	}
+	if got := os.Getenv("SIGNER_KEYSTORE_PASSWORD_FILE"); got != "" {
+		t.Errorf("the flag leaked into the environment: %q", got)
+	}
+
+	// The keystore is unlocked once per command
+	if again, _ := chain.signer(); again != s {
+		t.Error("expected the signer to be built once")
+	}
+}
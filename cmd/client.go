@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/client"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/payload"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// batchLine is a single task in a `performer client --batch` JSONL file. The
+// payload is passed to the configured codec: a JSON string is used as-is
+// (e.g. "0xdeadbeef" for hex), any other JSON value is given to the codec as
+// JSON (e.g. an array for abi, an object for json).
+type batchLine struct {
+	TaskId  string          `json:"taskId,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// runClient sends tasks to a running Performer over gRPC in one of three modes:
+// a single task (--payload), a batch of tasks from a JSONL file (--batch), or a
+// load test repeating those tasks (--requests with --concurrency).
+func runClient(args []string) error {
+	fs := flag.NewFlagSet("client", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "Performer gRPC address")
+	payloadFile := fs.String("payload", "-", "Payload file for a single task, or - to read from stdin")
+	batchFile := fs.String("batch", "", "JSONL file with one {\"taskId\": ..., \"payload\": ...} task per line")
+	format := fs.String("format", envOr("PERFORMER_PAYLOAD_FORMAT", "hex"), "Payload format: hex, raw, json or abi")
+	abiTypes := fs.String("abi", os.Getenv("PERFORMER_PAYLOAD_ABI"), "Solidity types for --format abi, e.g. \"(uint256,string)\"")
+	resultFormat := fs.String("result-format", "hex", "Result format used to decode results: hex, raw, json or abi")
+	resultABITypes := fs.String("result-abi", "", "Comma separated Solidity types for --result-format abi")
+	taskId := fs.String("task-id", "", "Hex task ID for a single task (default: keccak256 of the payload)")
+	requests := fs.Int("requests", 0, "Load test: total requests to send, repeating the tasks with unique task IDs")
+	concurrency := fs.Int("concurrency", 1, "Number of concurrent requests")
+	timeout := fs.Duration("timeout", 5*time.Second, "Per-request timeout")
+	output := fs.String("output", "text", "Output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	payloadCodec, err := payload.NewCodec(*format, *abiTypes)
+	if err != nil {
+		return err
+	}
+	resultCodec, err := payload.NewCodec(*resultFormat, *resultABITypes)
+	if err != nil {
+		return err
+	}
+
+	var tasks []*performerV1.TaskRequest
+	if *batchFile != "" {
+		if tasks, err = readBatch(*batchFile, payloadCodec); err != nil {
+			return err
+		}
+	} else {
+		task, err := readSingleTask(*payloadFile, *taskId, payloadCodec)
+		if err != nil {
+			return err
+		}
+		tasks = []*performerV1.TaskRequest{task}
+	}
+	if len(tasks) == 0 {
+		return fmt.Errorf("no tasks to send")
+	}
+
+	c, err := client.NewPerformerClient(*addr, *timeout)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	stats := client.NewStats()
+
+	// Load test: only the summary is printed
+	if *requests > 0 {
+		c.Run(ctx, *requests, *concurrency, func(i int) *performerV1.TaskRequest {
+			return loadTestTask(tasks[i%len(tasks)], i)
+		}, stats.Record)
+		summary := stats.Summary()
+		if err := printSummary(os.Stdout, *output, summary); err != nil {
+			return err
+		}
+		if summary.Failed > 0 {
+			return fmt.Errorf("%d of %d requests failed", summary.Failed, summary.Requests)
+		}
+		return nil
+	}
+
+	// Single task or batch: print every result, then the summary for batches
+	var mu sync.Mutex
+	var printErr error
+	c.Run(ctx, len(tasks), *concurrency, func(i int) *performerV1.TaskRequest {
+		return tasks[i]
+	}, func(r client.Result) {
+		stats.Record(r)
+
+		res := execResult{
+			TaskId:     hexutil.Encode(r.Task.TaskId),
+			Payload:    hexutil.Encode(r.Task.Payload),
+			DurationMs: r.Latency.Milliseconds(),
+		}
+		if r.Err != nil {
+			res.Error = r.Err.Error()
+		} else {
+			res.Result = hexutil.Encode(r.Response.Result)
+			decoded, err := resultCodec.Decode(r.Response.Result)
+			if err != nil {
+				res.Error = fmt.Sprintf("failed to decode result as %s: %v", resultCodec.Name(), err)
+			}
+			res.Decoded = decoded
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err := printExecResult(os.Stdout, *output, res); err != nil && printErr == nil {
+			printErr = err
+		}
+	})
+	if printErr != nil {
+		return printErr
+	}
+
+	summary := stats.Summary()
+	if len(tasks) > 1 {
+		if err := printSummary(os.Stdout, *output, summary); err != nil {
+			return err
+		}
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d of %d tasks failed", summary.Failed, summary.Requests)
+	}
+	return nil
+}
+
+func readSingleTask(path string, taskId string, codec payload.Codec) (*performerV1.TaskRequest, error) {
+	input, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := codec.Encode(input)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskRequest(taskId, data)
+}
+
+func readBatch(path string, codec payload.Codec) ([]*performerV1.TaskRequest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var tasks []*performerV1.TaskRequest
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var bl batchLine
+		if err := json.Unmarshal(line, &bl); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+
+		input := []byte(bl.Payload)
+		var s string
+		if json.Unmarshal(bl.Payload, &s) == nil {
+			input = []byte(s)
+		}
+		data, err := codec.Encode(input)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+
+		task, err := newTaskRequest(bl.TaskId, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, scanner.Err()
+}
+
+// newTaskRequest builds a TaskRequest, deriving the task ID from the payload
+// when none is given.
+func newTaskRequest(taskId string, data []byte) (*performerV1.TaskRequest, error) {
+	id := crypto.Keccak256(data)
+	if taskId != "" {
+		var err error
+		if id, err = hexutil.Decode(taskId); err != nil {
+			return nil, fmt.Errorf("invalid task ID: %w", err)
+		}
+	}
+	return &performerV1.TaskRequest{TaskId: id, Payload: data}, nil
+}
+
+// loadTestTask gives every request in a load test a unique task ID so it
+// cannot be mistaken for a retry.
+func loadTestTask(task *performerV1.TaskRequest, i int) *performerV1.TaskRequest {
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(i))
+	return &performerV1.TaskRequest{
+		TaskId:  crypto.Keccak256(task.TaskId, n[:]),
+		Payload: task.Payload,
+	}
+}
+
+func printSummary(out io.Writer, format string, s client.Summary) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	case "text":
+		fmt.Fprintf(out, "Requests:   %d (%d succeeded, %d failed)\n", s.Requests, s.Succeeded, s.Failed)
+		// Sorted like the JSON output, so that runs can be compared
+		codes := make([]string, 0, len(s.Errors))
+		for code := range s.Errors {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			fmt.Fprintf(out, "  %-22s %d\n", code+":", s.Errors[code])
+		}
+		fmt.Fprintf(out, "Elapsed:    %s (%.1f req/s)\n", s.Elapsed.Round(time.Millisecond), s.Throughput)
+		fmt.Fprintf(out, "Latency:    min %s  mean %s  p50 %s  p90 %s  p99 %s  max %s\n",
+			s.Min.Round(time.Microsecond), s.Mean.Round(time.Microsecond), s.P50.Round(time.Microsecond),
+			s.P90.Round(time.Microsecond), s.P99.Round(time.Microsecond), s.Max.Round(time.Microsecond))
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
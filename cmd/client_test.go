@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/client"
+)
+
+func Test_PrintSummarySortsErrorCodes(t *testing.T) {
+	s := client.Summary{
+		Requests: 6,
+		Failed:   6,
+		Errors:   map[string]int{"Unavailable": 1, "DeadlineExceeded": 2, "ResourceExhausted": 3},
+	}
+
+	var out bytes.Buffer
+	if err := printSummary(&out, "text", s); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	deadline := strings.Index(text, "DeadlineExceeded:")
+	exhausted := strings.Index(text, "ResourceExhausted:")
+	unavailable := strings.Index(text, "Unavailable:")
+	if deadline < 0 || !(deadline < exhausted && exhausted < unavailable) {
+		t.Errorf("expected error codes in sorted order, got:\n%s", text)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/reverts"
+)
+
+// command is a Performer subcommand, e.g. `performer exec`. Running the binary
+// without a subcommand starts the Performer gRPC server.
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+// errReported is returned by commands that already printed their error, so
+// that only the exit status reports it.
+var errReported = errors.New("error already reported")
+
+var commands = map[string]command{
+	"exec":         {usage: "Run a single task in-process from a payload file or stdin", run: runExec},
+	"client":       {usage: "Send tasks to a running Performer over gRPC (single, batch or load test)", run: runClient},
+	"simulate":     {usage: "Run a task on several simulated operators and check their results agree", run: runSimulate},
+	"socket":       {usage: "Show or update the operator's socket on the TaskAVSRegistrar", run: runSocket},
+	"registration": {usage: "Register or deregister the operator with the AVS, or show its status", run: runRegistration},
+	"taskconfig":   {usage: "Show, diff or apply an executor operator set's TaskMailbox config", run: runTaskConfig},
+	"task":         {usage: "Show a task's status, timeline, fee, payload and result from the TaskMailbox", run: runTask},
+	"replay":       {usage: "Replay historical tasks from chain, or from fixtures, and report changed results", run: runReplay},
+	"journal":      {usage: "Summarize the last tasks in the result journal and any divergence from certified results", run: runJournal},
+}
+
+// runCommand dispatches os.Args to a subcommand. It reports false if args do
+// not name one, in which case the Performer server should be started.
+func runCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return true
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		return false
+	}
+
+	if err := cmd.run(args[1:]); err != nil {
+		// Show contract reverts by their custom error rather than as hex
+		err = reverts.Decode(err)
+		if !errors.Is(err, flag.ErrHelp) && !errors.Is(err, errReported) {
+			fmt.Fprintf(os.Stderr, "performer %s: %v\n", name, err)
+		}
+		os.Exit(1)
+	}
+	return true
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: performer [command] [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Without a command the Performer gRPC server is started.")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", name, commands[name].usage)
+	}
+}
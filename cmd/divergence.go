@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/divergence"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/metrics"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// defaultDivergenceInterval is how often TaskVerified events are checked.
+const defaultDivergenceInterval = 15 * time.Second
+
+// newDivergenceAlarm builds the alarm that compares the results in journal
+// with the ones certified for the Performer's AVS.
+func newDivergenceAlarm(ctx context.Context, cfg *TaskWorkerConfig, journal *divergence.Journal, taskMailbox *mailbox.TaskMailbox, l2Client *ethclient.Client, registry *bindings.Registry) (*divergence.Alarm, error) {
+	avs, err := avsAddress(ctx, cfg.Verify.Avs, registry)
+	if err != nil {
+		return nil, err
+	}
+	return &divergence.Alarm{
+		Journal: journal,
+		Events:  taskMailbox,
+		Heads:   l2Client,
+		Avs:     avs,
+		Webhook: cfg.DivergenceWebhook,
+	}, nil
+}
+
+// recordResult journals the result of t, if the journal is enabled and t's ID
+// is a task hash.
+func (tw *TaskWorker) recordResult(t *performerV1.TaskRequest, result []byte) {
+	if tw.journal == nil || len(t.GetTaskId()) != common.HashLength {
+		return
+	}
+	if err := tw.journal.Record(common.BytesToHash(t.GetTaskId()), result, time.Now()); err != nil {
+		tw.logger.Error("Failed to journal task result", zap.Binary("taskId", t.GetTaskId()), zap.Error(err))
+	}
+}
+
+// reportDivergence raises the alarm for a task whose certified result is not
+// ours.
+func (tw *TaskWorker) reportDivergence(d divergence.Divergence) {
+	metrics.TasksDiverged.Inc(1)
+	tw.logger.Error("Certified result differs from ours",
+		zap.String("taskHash", d.TaskHash.Hex()),
+		zap.String("resultHash", d.ResultHash.Hex()),
+		zap.String("certifiedResultHash", d.CertifiedResultHash.Hex()),
+		zap.String("aggregator", d.Aggregator.Hex()),
+		zap.Uint64("block", d.Block),
+		zap.String("txHash", d.TxHash.Hex()),
+	)
+}
+
+// watchDivergence compares journaled results with certified ones until ctx is
+// done.
+func (tw *TaskWorker) watchDivergence(ctx context.Context, interval time.Duration) {
+	if tw.divergenceAlarm == nil {
+		return
+	}
+	tw.divergenceAlarm.Watch(ctx, interval, func(err error) {
+		tw.logger.Error("Failed to check certified results", zap.Error(err))
+	})
+}
+
+// runJournal summarizes the last tasks in the result journal:
+//
+//	performer journal --last 20
+func runJournal(args []string) error {
+	fs := flag.NewFlagSet("journal", flag.ContinueOnError)
+	file := fs.String("file", os.Getenv("PERFORMER_JOURNAL_FILE"), "Result journal (default $PERFORMER_JOURNAL_FILE)")
+	last := fs.Int("last", 20, "Number of most recent tasks to show")
+	output := fs.String("output", "text", "Output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("--file is required")
+	}
+	if *last <= 0 {
+		return fmt.Errorf("--last must be positive")
+	}
+
+	journal, err := divergence.ReadJournal(*file)
+	if err != nil {
+		return err
+	}
+	entries := journal.Last(*last)
+	summary := divergence.Summarize(entries)
+	if err := printJournal(os.Stdout, *output, journal.Checkpoint(), summary, entries); err != nil {
+		return err
+	}
+	if summary.Diverged > 0 {
+		return fmt.Errorf("%d of the last %d tasks were certified with a different result", summary.Diverged, summary.Tasks)
+	}
+	return nil
+}
+
+func printJournal(out io.Writer, format string, checkpoint uint64, summary divergence.Summary, entries []divergence.Entry) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Checkpoint uint64             `json:"checkpoint"`
+			Summary    divergence.Summary `json:"summary"`
+			Tasks      []divergence.Entry `json:"tasks"`
+		}{checkpoint, summary, entries})
+	case "text":
+		for _, e := range entries {
+			status := "pending"
+			switch {
+			case e.Diverged:
+				status = "DIVERGED"
+			case e.Verified:
+				status = "matched"
+			}
+			fmt.Fprintf(out, "  %s  %s  %-8s result %s", e.TaskHash, e.HandledAt.UTC().Format(time.RFC3339), status, e.ResultHash)
+			if e.Diverged {
+				fmt.Fprintf(out, ", certified %s (block %d)", e.CertifiedResultHash, e.VerifiedBlock)
+			}
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "\nLast %d tasks: %d matched, %d diverged, %d pending. TaskVerified events checked up to block %d.\n",
+			summary.Tasks, summary.Matched, summary.Diverged, summary.Pending, checkpoint)
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
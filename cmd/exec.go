@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/payload"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/reverts"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+// execResult is printed by `performer exec --output json`.
+type execResult struct {
+	TaskId     string `json:"taskId"`
+	Payload    string `json:"payload"`
+	Result     string `json:"result,omitempty"`
+	Decoded    any    `json:"decoded,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// runExec builds a TaskRequest from a payload file (or stdin) and runs it
+// through ValidateTask and HandleTask in-process, without the Aggregator,
+// Executor or devnet.
+func runExec(args []string) error {
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	payloadFile := fs.String("payload", "-", "Payload file, or - to read from stdin")
+	format := fs.String("format", envOr("PERFORMER_PAYLOAD_FORMAT", "hex"), "Payload format: hex, raw, json or abi")
+	abiTypes := fs.String("abi", os.Getenv("PERFORMER_PAYLOAD_ABI"), "Solidity types for --format abi, e.g. \"(uint256,string)\"")
+	resultFormat := fs.String("result-format", "hex", "Result format used to decode the result: hex, raw, json or abi")
+	resultABITypes := fs.String("result-abi", "", "Comma separated Solidity types for --result-format abi")
+	taskId := fs.String("task-id", "", "Hex task ID (default: keccak256 of the payload)")
+	l1RpcUrl := fs.String("l1-rpc-url", os.Getenv("L1_RPC_URL"), "L1 RPC URL")
+	l2RpcUrl := fs.String("l2-rpc-url", os.Getenv("L2_RPC_URL"), "L2 RPC URL")
+	contractsEnv := fs.String("contracts-env", os.Getenv("PERFORMER_CONTRACTS_ENV"), "Read contract addresses from this devkit environment's deploy outputs, e.g. devnet")
+	output := fs.String("output", "text", "Output format: text or json")
+	verbose := fs.Bool("verbose", false, "Print Performer logs to stderr")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	payloadCodec, err := payload.NewCodec(*format, *abiTypes)
+	if err != nil {
+		return err
+	}
+	resultCodec, err := payload.NewCodec(*resultFormat, *resultABITypes)
+	if err != nil {
+		return err
+	}
+
+	task, err := readSingleTask(*payloadFile, *taskId, payloadCodec)
+	if err != nil {
+		return err
+	}
+
+	logger := zap.NewNop()
+	if *verbose {
+		if logger, err = zap.NewDevelopment(); err != nil {
+			return err
+		}
+	}
+
+	cfg := TaskWorkerConfigFromEnv(logger)
+	cfg.L1RpcUrl = *l1RpcUrl
+	cfg.L2RpcUrl = *l2RpcUrl
+	cfg.Contracts.Environment = *contractsEnv
+	w := NewTaskWorkerWithConfig(logger, cfg)
+
+	res := execResult{
+		TaskId:  hexutil.Encode(task.TaskId),
+		Payload: hexutil.Encode(task.Payload),
+	}
+
+	start := time.Now()
+	resp, taskErr := execTask(w, task)
+	res.DurationMs = time.Since(start).Milliseconds()
+
+	if taskErr != nil {
+		res.Error = reverts.Decode(taskErr).Error()
+	} else {
+		res.Result = hexutil.Encode(resp.Result)
+		if res.Decoded, err = resultCodec.Decode(resp.Result); err != nil {
+			res.Error = fmt.Sprintf("failed to decode result as %s: %v", resultCodec.Name(), err)
+		}
+	}
+
+	if err := printExecResult(os.Stdout, *output, res); err != nil {
+		return err
+	}
+	if taskErr != nil {
+		// The error is part of the printed result
+		return errReported
+	}
+	return nil
+}
+
+// execTask runs a task the same way the Executor does: validation first, then
+// handling.
+func execTask(w *TaskWorker, task *performerV1.TaskRequest) (*performerV1.TaskResponse, error) {
+	if err := w.ValidateTask(task); err != nil {
+		return nil, fmt.Errorf("task validation failed: %w", err)
+	}
+	resp, err := w.HandleTask(task)
+	if err != nil {
+		return nil, fmt.Errorf("task handling failed: %w", err)
+	}
+	return resp, nil
+}
+
+func envOr(name string, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" || path == "" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func printExecResult(out io.Writer, format string, res execResult) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	case "text":
+		fmt.Fprintf(out, "Task ID:  %s\n", res.TaskId)
+		fmt.Fprintf(out, "Payload:  %s\n", res.Payload)
+		fmt.Fprintf(out, "Duration: %dms\n", res.DurationMs)
+		if res.Error != "" {
+			fmt.Fprintf(out, "Error:    %s\n", res.Error)
+			return nil
+		}
+		fmt.Fprintf(out, "Result:   %s\n", res.Result)
+		decoded, err := json.Marshal(res.Decoded)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Decoded:  %s\n", decoded)
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
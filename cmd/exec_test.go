@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"go.uber.org/zap"
+)
+
+func Test_ExecTask(t *testing.T) {
+	// An explicit config keeps PERFORMER_* settings in the environment out of
+	// the test
+	w := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{})
+
+	req := &performerV1.TaskRequest{
+		TaskId:  []byte("exec-task-id"),
+		Payload: []byte("test-data"),
+	}
+	resp, err := execTask(w, req)
+	if err != nil {
+		t.Fatalf("execTask failed: %v", err)
+	}
+	if !bytes.Equal(resp.TaskId, req.TaskId) {
+		t.Errorf("TaskId = %q, want %q", resp.TaskId, req.TaskId)
+	}
+	// The template handler returns an empty result
+	if len(resp.Result) != 0 {
+		t.Errorf("Result = %x, want empty", resp.Result)
+	}
+
+	var out bytes.Buffer
+	res := execResult{TaskId: "0x01", Payload: "0x02", Result: "0x", Decoded: "0x"}
+	if err := printExecResult(&out, "json", res); err != nil {
+		t.Fatalf("printExecResult failed: %v", err)
+	}
+
+	var decoded execResult
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("exec output is not valid JSON: %v", err)
+	}
+	if decoded.TaskId != res.TaskId || decoded.Result != res.Result {
+		t.Errorf("unexpected exec output: %s", out.String())
+	}
+
+	if err := printExecResult(&out, "yaml", res); err == nil {
+		t.Error("expected unknown output format to fail")
+	}
+}
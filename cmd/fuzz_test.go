@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/golden"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/payload"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/recovery"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"go.uber.org/zap"
+)
+
+// fuzzPayloadABI is the ABI signature of the task payload, in the form passed
+// to `devkit avs call`. Well-formed payloads for it seed the fuzzer. Override
+// it with PERFORMER_PAYLOAD_ABI.
+const fuzzPayloadABI = "(uint256,string)"
+
+// addFuzzSeeds seeds f with the golden task payloads and with well-formed
+// payloads for the payload ABI.
+func addFuzzSeeds(f *testing.F) {
+	cases, err := golden.LoadCases(goldenTasksDir)
+	if err != nil {
+		f.Fatalf("Failed to load golden tasks: %v", err)
+	}
+	for _, c := range cases {
+		f.Add(c.Payload)
+	}
+
+	codec, err := payload.NewABICodec(envOr("PERFORMER_PAYLOAD_ABI", fuzzPayloadABI))
+	if err != nil {
+		f.Fatalf("Invalid payload ABI: %v", err)
+	}
+	samples, err := codec.Samples()
+	if err != nil {
+		f.Fatalf("Failed to build ABI samples: %v", err)
+	}
+	for _, s := range samples {
+		f.Add(s)
+		// Truncated payloads are a common source of decoder panics
+		f.Add(s[:len(s)/2])
+	}
+}
+
+// newFuzzWorker creates a worker without RPC endpoints so results depend only
+// on the payload.
+func newFuzzWorker() *TaskWorker {
+	return NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{})
+}
+
+// FuzzValidateTask asserts that ValidateTask never panics, whatever the payload.
+//
+//	go test ./cmd -run '^$' -fuzz FuzzValidateTask -fuzztime 1m
+func FuzzValidateTask(f *testing.F) {
+	addFuzzSeeds(f)
+	w := newFuzzWorker()
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err := w.ValidateTask(&performerV1.TaskRequest{TaskId: []byte("fuzz"), Payload: data})
+		if errors.Is(err, recovery.ErrTaskPanicked) {
+			t.Fatalf("ValidateTask panicked on payload %x: %v", data, err)
+		}
+	})
+}
+
+// FuzzHandleTask asserts that every payload accepted by ValidateTask is
+// handled without error. Malformed payloads must be rejected by validation,
+// not fail (or panic) in HandleTask.
+//
+//	go test ./cmd -run '^$' -fuzz FuzzHandleTask -fuzztime 1m
+func FuzzHandleTask(f *testing.F) {
+	addFuzzSeeds(f)
+	w := newFuzzWorker()
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		task := &performerV1.TaskRequest{TaskId: []byte("fuzz"), Payload: data}
+
+		if err := w.ValidateTask(task); err != nil {
+			if errors.Is(err, recovery.ErrTaskPanicked) {
+				t.Fatalf("ValidateTask panicked on payload %x: %v", data, err)
+			}
+			return
+		}
+
+		resp, err := w.HandleTask(task)
+		if err != nil {
+			t.Fatalf("HandleTask failed on a payload that passed validation %x: %v", data, err)
+		}
+		if resp == nil {
+			t.Fatalf("HandleTask returned no response for payload %x", data)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/golden"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"go.uber.org/zap"
+)
+
+// update regenerates the golden files in testdata/tasks:
+//
+//	go test ./cmd -run Test_GoldenTasks -update
+var update = flag.Bool("update", false, "update golden task results in testdata/tasks")
+
+const goldenTasksDir = "testdata/tasks"
+
+func Test_GoldenTasks(t *testing.T) {
+	// ------------------------------------------------------------------------
+	// Add a directory to testdata/tasks for every payload you want to pin down
+	// ------------------------------------------------------------------------
+
+	cases, err := golden.LoadCases(goldenTasksDir)
+	if err != nil {
+		t.Fatalf("Failed to load golden tasks: %v", err)
+	}
+
+	// An explicit config keeps PERFORMER_* settings in the environment out of
+	// the test
+	w := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{})
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			resp, err := execTask(w, &performerV1.TaskRequest{
+				TaskId:  c.TaskId,
+				Payload: c.Payload,
+			})
+
+			var result []byte
+			if resp != nil {
+				result = resp.Result
+			}
+
+			if *update {
+				if err := c.Update(result, err); err != nil {
+					t.Fatalf("Failed to update golden files: %v", err)
+				}
+				return
+			}
+			if err := c.Check(result, err); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
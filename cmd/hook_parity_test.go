@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/avstaskhook"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/golden"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// The hook parity test deploys AVSTaskHook to a disposable chain and runs the
+// golden task fixtures through both the on-chain hook and the Go validator.
+// `make test-hook-parity` (part of `make test`) starts anvil and runs it, or
+// point it at a running chain yourself:
+//
+//	HOOK_PARITY_RPC_URL=http://localhost:8545 go test ./cmd -run Test_HookParity
+//
+// Without a chain the test is skipped, except in CI (CI set), where it fails
+// so that parity is never silently unchecked.
+//
+// ethclient/simulated can't be used in this module: it pulls in core/rawdb,
+// which does not build against the tablewriter v1 this module requires.
+const (
+	// anvil's first default account; only ever use it on a local chain
+	hookParityDefaultKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+	hookParityOperatorSetId = 1
+)
+
+// newHookParityHook deploys a fresh AVSTaskHook, skipping the test if no
+// chain is configured outside CI.
+func newHookParityHook(t *testing.T, ctx context.Context) (*avstaskhook.AVSTaskHook, common.Address) {
+	t.Helper()
+
+	rpcUrl := os.Getenv("HOOK_PARITY_RPC_URL")
+	if rpcUrl == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("HOOK_PARITY_RPC_URL not set; CI must run the AVSTaskHook parity test with `make test-hook-parity`")
+		}
+		t.Skip("HOOK_PARITY_RPC_URL not set; start anvil to run the AVSTaskHook parity test")
+	}
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	if err != nil {
+		t.Fatalf("Failed to connect to %s: %v", rpcUrl, err)
+	}
+	t.Cleanup(client.Close)
+
+	deployer, err := signer.NewPrivateKeySigner(envOr("HOOK_PARITY_PRIVATE_KEY", hookParityDefaultKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := signer.NewTransactor(ctx, deployer, client, signer.GasPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := tr.TransactOpts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, tx, hook, err := avstaskhook.DeployAVSTaskHook(opts, client)
+	if err != nil {
+		t.Fatalf("Failed to deploy AVSTaskHook: %v", err)
+	}
+	if _, err := bind.WaitDeployed(ctx, client, tx); err != nil {
+		t.Fatalf("AVSTaskHook deployment failed: %v", err)
+	}
+	return hook, deployer.Address()
+}
+
+func Test_HookParity(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	hook, caller := newHookParityHook(t, ctx)
+
+	cases, err := golden.LoadCases(goldenTasksDir)
+	if err != nil {
+		t.Fatalf("Failed to load golden tasks: %v", err)
+	}
+
+	w := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{})
+	callOpts := &bind.CallOpts{Context: ctx, From: caller}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			task := &performerV1.TaskRequest{TaskId: c.TaskId, Payload: c.Payload}
+
+			// Task creation: validatePreTaskCreation must accept exactly the
+			// payloads ValidateTask accepts
+			goErr := w.ValidateTask(task)
+			chainErr := hook.ValidatePreTaskCreation(callOpts, caller, avstaskhook.ITaskMailboxTypesTaskParams{
+				ExecutorOperatorSet: avstaskhook.OperatorSet{Avs: caller, Id: hookParityOperatorSetId},
+				Payload:             c.Payload,
+			})
+			if (goErr == nil) != (chainErr == nil) {
+				t.Fatalf("validatePreTaskCreation and ValidateTask disagree: on-chain error %v, Go error %v", chainErr, goErr)
+			}
+			if goErr != nil {
+				return
+			}
+
+			// Result submission: the hook must accept the result HandleTask
+			// produces, and reject the task if HandleTask fails
+			resp, goErr := w.HandleTask(task)
+			var result []byte
+			if resp != nil {
+				result = resp.Result
+			}
+			chainErr = hook.ValidatePreTaskResultSubmission(callOpts, caller, common.BytesToHash(c.TaskId), []byte{}, result)
+			if (goErr == nil) != (chainErr == nil) {
+				t.Fatalf("validatePreTaskResultSubmission and HandleTask disagree: on-chain error %v, Go error %v", chainErr, goErr)
+			}
+		})
+	}
+}
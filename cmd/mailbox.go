@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/deployments"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/metrics"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// taskMailboxEnvName is the contract store name of the L2 TaskMailbox.
+const taskMailboxEnvName = "TASK_MAILBOX"
+
+// mailboxTimeout bounds TaskMailbox calls made for a single task.
+const mailboxTimeout = 10 * time.Second
+
+// newTaskMailbox binds the TaskMailbox in the contract store to the L2 client.
+func newTaskMailbox(store *deployments.Store, l2Client *ethclient.Client) (*mailbox.TaskMailbox, error) {
+	if l2Client == nil {
+		return nil, errors.New("the TaskMailbox needs an L2 RPC URL")
+	}
+	if store == nil {
+		return nil, errors.New("the TaskMailbox needs the contract store")
+	}
+	address, err := store.GetContract(taskMailboxEnvName)
+	if err != nil {
+		return nil, fmt.Errorf("no %s address: %w", taskMailboxEnvName, err)
+	}
+	return mailbox.NewTaskMailbox(address, l2Client)
+}
+
+// newTaskVerifier builds the verifier for cfg.
+func newTaskVerifier(ctx context.Context, cfg mailbox.VerifierConfig, taskMailbox *mailbox.TaskMailbox, registry *bindings.Registry) (*mailbox.Verifier, error) {
+	avs, err := avsAddress(ctx, cfg.Avs, registry)
+	if err != nil {
+		return nil, err
+	}
+	return mailbox.NewVerifier(taskMailbox, avs, cfg.ExecutorOperatorSetId), nil
+}
+
+// avsAddress returns avs, or if it is zero, the AVS the TaskAVSRegistrar is
+// configured with.
+func avsAddress(ctx context.Context, avs common.Address, registry *bindings.Registry) (common.Address, error) {
+	if avs != (common.Address{}) {
+		return avs, nil
+	}
+	registrar, err := registry.TaskAVSRegistrar()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("AVS_ADDRESS or the TaskAVSRegistrar is required: %w", err)
+	}
+	if avs, err = registrar.Avs(&bind.CallOpts{Context: ctx}); err != nil {
+		return common.Address{}, fmt.Errorf("failed to read avs from the TaskAVSRegistrar: %w", err)
+	}
+	return avs, nil
+}
+
+// verificationEnabled reports whether tasks must be verified, including when
+// verification is enabled but could not be set up.
+func (tw *TaskWorker) verificationEnabled() bool {
+	return tw.verifier != nil || tw.verifierErr != nil
+}
+
+// needsTaskInfo reports whether any configured check uses the task's
+// TaskMailbox entry.
+func (tw *TaskWorker) needsTaskInfo() bool {
+	return tw.verificationEnabled() || tw.sla.Enabled() || tw.policy != nil || tw.fees != nil
+}
+
+// lookupTask reads t from the TaskMailbox if a check needs it. It returns nil
+// when no check needs it, the TaskMailbox is unavailable, or t's ID is not a
+// task hash. Lookup failures only fail the task when verification is enabled.
+func (tw *TaskWorker) lookupTask(t *performerV1.TaskRequest) (*mailbox.Task, error) {
+	if tw.verifierErr != nil {
+		return nil, fmt.Errorf("task verification is unavailable: %w", tw.verifierErr)
+	}
+	if !tw.needsTaskInfo() {
+		return nil, nil
+	}
+	if tw.taskMailbox == nil || len(t.GetTaskId()) != common.HashLength {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mailboxTimeout)
+	defer cancel()
+
+	hash := common.BytesToHash(t.GetTaskId())
+	task, err := tw.taskMailbox.GetTaskInfo(ctx, hash)
+	if err != nil {
+		err = fmt.Errorf("failed to read task %s from the TaskMailbox: %w", hash, err)
+		if tw.verificationEnabled() {
+			return nil, err
+		}
+		tw.logger.Warn("Admitting task without TaskMailbox info", zap.Error(err))
+		return nil, nil
+	}
+	return task, nil
+}
+
+// verifyTask checks t against task, its TaskMailbox entry, when verification
+// is enabled.
+func (tw *TaskWorker) verifyTask(t *performerV1.TaskRequest, task *mailbox.Task) error {
+	if tw.verifier == nil {
+		return nil
+	}
+	if task == nil {
+		task = &mailbox.Task{}
+	}
+	if err := tw.verifier.Check(t.GetTaskId(), task, t.GetPayload()); err != nil {
+		metrics.TasksUnverified.Inc(1)
+		tw.logger.Warn("Rejecting task that failed verification", zap.Binary("taskId", t.GetTaskId()), zap.Error(err))
+		return err
+	}
+	return nil
+}
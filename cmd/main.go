@@ -2,15 +2,26 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
 	"time"
 
-	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/helloworldl1"
-	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/taskavsregistrar"
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/admission"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/deployments"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/divergence"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/limiter"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/metrics"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/recovery"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/taskmetadata"
 	"github.com/Layr-Labs/hourglass-monorepo/ponos/pkg/performer/contracts"
 	"github.com/Layr-Labs/hourglass-monorepo/ponos/pkg/performer/server"
 	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"go.uber.org/zap"
 )
@@ -22,50 +33,393 @@ import (
 // return the result to the Executor where the result is signed and return to the
 // Aggregator to place in the outbox once the signing threshold is met.
 
+// defaultHandler is the limiter key used for tasks handled by HandleTask.
+// Register additional handler names in PERFORMER_HANDLER_LIMITS if your AVS
+// routes tasks to handlers with different resource profiles.
+const defaultHandler = "default"
+
+// serverTimeout is how long the gRPC server waits for a task's response.
+const serverTimeout = 5 * time.Second
+
 type TaskWorker struct {
-	logger        *zap.Logger
-	contractStore *contracts.ContractStore
-	l1Client      *ethclient.Client
-	l2Client      *ethclient.Client
+	logger          *zap.Logger
+	contractStore   *deployments.Store
+	l1Client        *ethclient.Client
+	l2Client        *ethclient.Client
+	contracts       *bindings.Registry
+	taskMailbox     *mailbox.TaskMailbox
+	verifier        *mailbox.Verifier
+	verifierErr     error
+	sla             admission.SLAConfig
+	fees            *admission.FeeChecker
+	feesErr         error
+	policy          *admission.Policy
+	policyFile      string
+	policyErr       error
+	admitted        *admittedTasks
+	taskMetadata    *taskmetadata.Watcher
+	taskMetadataErr error
+	journal         *divergence.Journal
+	divergenceAlarm *divergence.Alarm
+	limits          limiter.Config
+	audit           *zap.Logger
+	taskLimiter     *limiter.TaskLimiter
+	clock           func() time.Time
+	maxQueueWait    time.Duration
+}
+
+// TaskWorkerConfig configures a TaskWorker. NewTaskWorker reads it from the
+// environment; tests and tools can build one directly.
+type TaskWorkerConfig struct {
+	L1RpcUrl string
+	L2RpcUrl string
+	Limits   *limiter.Config
+
+	// Clock is the time source for task logic. Operators must produce identical
+	// results, so use tw.now() rather than time.Now() in your handlers.
+	Clock func() time.Time
+
+	// RPCTransport, if set, carries the HTTP traffic of the L1 and L2 clients.
+	RPCTransport http.RoundTripper
+	// L1RPCTransport and L2RPCTransport, if set, carry the traffic of one
+	// client instead of RPCTransport.
+	L1RPCTransport http.RoundTripper
+	L2RPCTransport http.RoundTripper
+
+	// Verify enables checking each task against the L2 TaskMailbox before it
+	// is handled.
+	Verify    mailbox.VerifierConfig
+	verifyErr error
+
+	// SLA holds each handler's expected duration. Tasks that can't finish
+	// before their deadline are rejected.
+	SLA admission.SLAConfig
+
+	// Fees holds the minimum fee of each handler. Tasks that pay less are
+	// rejected.
+	Fees    admission.FeeConfig
+	feesErr error
+
+	// PolicyFile, if set, is a JSON file of creator allow and deny lists and
+	// quotas. The server reloads it when it changes.
+	PolicyFile string
+
+	// TaskMetadata enables reading Performer settings from the taskMetadata of
+	// the executor operator set's TaskMailbox config. Changes apply live.
+	TaskMetadata    bool
+	taskMetadataErr error
+
+	// JournalFile, if set, is where the hash of every result is journaled.
+	// Results are compared with the ones certified in TaskVerified events.
+	JournalFile string
+	// JournalSize is the number of tasks the journal keeps.
+	JournalSize int
+	// DivergenceWebhook, if set, receives a JSON POST for every task whose
+	// certified result differs from ours.
+	DivergenceWebhook string
+
+	// Contracts selects devkit deploy outputs to read contract addresses from,
+	// in addition to the environment variables set by the executor.
+	Contracts deployments.Config
+}
+
+// TaskWorkerConfigFromEnv reads the TaskWorker configuration from the environment.
+func TaskWorkerConfigFromEnv(logger *zap.Logger) *TaskWorkerConfig {
+	// Initialize concurrency and RPC rate limits
+	limits, err := limiter.ConfigFromEnv()
+	if err != nil {
+		logger.Warn("Failed to load limiter config, running without limits", zap.Error(err))
+		limits = &limiter.Config{}
+	}
+
+	contractsCfg, err := deployments.ConfigFromEnv()
+	if err != nil {
+		logger.Warn("Failed to load contract address config, using environment variables only", zap.Error(err))
+		contractsCfg = deployments.Config{}
+	}
+
+	verifyCfg, verifyErr := mailbox.VerifierConfigFromEnv()
+
+	slaCfg, err := admission.SLAConfigFromEnv()
+	if err != nil {
+		logger.Warn("Failed to load SLA config, admitting tasks regardless of deadline", zap.Error(err))
+		slaCfg = admission.SLAConfig{}
+	}
+
+	feeCfg, feesErr := admission.FeeConfigFromEnv()
+
+	var taskMetadata bool
+	var taskMetadataErr error
+	if v := os.Getenv("PERFORMER_TASK_METADATA"); v != "" {
+		if taskMetadata, err = strconv.ParseBool(v); err != nil {
+			taskMetadataErr = fmt.Errorf("invalid PERFORMER_TASK_METADATA %q: %w", v, err)
+		}
+	}
+
+	journalSize := divergence.DefaultJournalSize
+	if v := os.Getenv("PERFORMER_JOURNAL_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+			logger.Warn("Invalid PERFORMER_JOURNAL_SIZE, using the default", zap.String("value", v))
+		} else {
+			journalSize = n
+		}
+	}
+
+	return &TaskWorkerConfig{
+		L1RpcUrl:   os.Getenv("L1_RPC_URL"),
+		L2RpcUrl:   os.Getenv("L2_RPC_URL"),
+		Limits:     limits,
+		Verify:     verifyCfg,
+		verifyErr:  verifyErr,
+		SLA:        slaCfg,
+		Fees:       feeCfg,
+		feesErr:    feesErr,
+		PolicyFile: os.Getenv("PERFORMER_POLICY_FILE"),
+		Contracts:  contractsCfg,
+
+		TaskMetadata:    taskMetadata,
+		taskMetadataErr: taskMetadataErr,
+
+		JournalFile:       os.Getenv("PERFORMER_JOURNAL_FILE"),
+		JournalSize:       journalSize,
+		DivergenceWebhook: os.Getenv("PERFORMER_DIVERGENCE_WEBHOOK"),
+	}
 }
 
 func NewTaskWorker(logger *zap.Logger) *TaskWorker {
-	// Initialize contract store from environment variables
-	contractStore, err := contracts.NewContractStore()
+	return NewTaskWorkerWithConfig(logger, TaskWorkerConfigFromEnv(logger))
+}
+
+func NewTaskWorkerWithConfig(logger *zap.Logger, cfg *TaskWorkerConfig) *TaskWorker {
+	// Initialize contract store from environment variables and, if configured,
+	// devkit deploy outputs
+	contractStore, err := loadContractStore(cfg.Contracts)
 	if err != nil {
 		logger.Warn("Failed to load contract store", zap.Error(err))
 	}
 
-	// Initialize Ethereum clients if RPC URLs are provided
+	limits := cfg.Limits
+	if limits == nil {
+		limits = &limiter.Config{}
+	}
+
+	clock := cfg.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	// Initialize Ethereum clients if RPC URLs are provided. Every binding built on
+	// a client shares that endpoint's rate limit.
 	var l1Client, l2Client *ethclient.Client
 
-	if l1RpcUrl := os.Getenv("L1_RPC_URL"); l1RpcUrl != "" {
-		l1Client, err = ethclient.Dial(l1RpcUrl)
+	if cfg.L1RpcUrl != "" {
+		l1Client, err = limiter.DialRateLimited(context.Background(), cfg.L1RpcUrl, limits.L1RPC, orTransport(cfg.L1RPCTransport, cfg.RPCTransport))
 		if err != nil {
 			logger.Error("Failed to connect to L1 RPC", zap.Error(err))
 		}
 	}
 
-	if l2RpcUrl := os.Getenv("L2_RPC_URL"); l2RpcUrl != "" {
-		l2Client, err = ethclient.Dial(l2RpcUrl)
+	if cfg.L2RpcUrl != "" {
+		l2Client, err = limiter.DialRateLimited(context.Background(), cfg.L2RpcUrl, limits.L2RPC, orTransport(cfg.L2RPCTransport, cfg.RPCTransport))
 		if err != nil {
 			logger.Error("Failed to connect to L2 RPC", zap.Error(err))
 		}
 	}
 
-	return &TaskWorker{
-		logger:        logger,
-		contractStore: contractStore,
-		l1Client:      l1Client,
-		l2Client:      l2Client,
+	var addresses bindings.AddressSource
+	if contractStore != nil {
+		addresses = contractStore
+	}
+	registry := bindings.NewRegistry(addresses, backend(l1Client), backend(l2Client))
+
+	// The TaskMailbox on L2 holds each task's creator, deadline and payload
+	taskMailbox, mailboxErr := newTaskMailbox(contractStore, l2Client)
+	if mailboxErr != nil && cfg.SLA.Enabled() {
+		logger.Warn("TaskMailbox unavailable, only payload deadlines are enforced", zap.Error(mailboxErr))
+	}
+
+	// Fee checks fail closed too: if a minimum fee is configured but can't be
+	// checked, every task is rejected.
+	var fees *admission.FeeChecker
+	feesErr := cfg.feesErr
+	if feesErr == nil && cfg.Fees.Enabled() {
+		if l2Client == nil {
+			feesErr = errors.New("fee checks need an L2 RPC URL")
+		} else if decimals, err := admission.NewERC20Decimals(l2Client); err != nil {
+			feesErr = err
+		} else {
+			fees = admission.NewFeeChecker(cfg.Fees, decimals)
+		}
+	}
+	if feesErr != nil {
+		logger.Error("Failed to set up fee checks, rejecting all tasks", zap.Error(feesErr))
+	}
+
+	// The creator policy fails closed like verification: a policy file that
+	// can't be loaded rejects every task.
+	var policy *admission.Policy
+	var policyErr error
+	if cfg.PolicyFile != "" {
+		if policy, policyErr = admission.NewPolicyFromFile(cfg.PolicyFile); policyErr != nil {
+			logger.Error("Failed to load policy, rejecting all tasks", zap.Error(policyErr))
+		}
+	}
+
+	// Verification fails closed: if it is misconfigured, every task is
+	// rejected rather than handled unverified.
+	var verifier *mailbox.Verifier
+	verifierErr := cfg.verifyErr
+	if verifierErr == nil && cfg.Verify.Enabled {
+		if mailboxErr != nil {
+			verifierErr = mailboxErr
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), mailboxTimeout)
+			verifier, verifierErr = newTaskVerifier(ctx, cfg.Verify, taskMailbox, registry)
+			cancel()
+		}
+	}
+	if verifierErr != nil {
+		logger.Error("Failed to set up task verification, rejecting all tasks", zap.Error(verifierErr))
+	}
+
+	// Task metadata fails closed as well: if it is enabled but can't be read,
+	// every handler is treated as disabled.
+	var taskMetadata *taskmetadata.Watcher
+	taskMetadataErr := cfg.taskMetadataErr
+	if taskMetadataErr == nil && cfg.TaskMetadata {
+		if mailboxErr != nil {
+			taskMetadataErr = mailboxErr
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), mailboxTimeout)
+			taskMetadata, taskMetadataErr = newTaskMetadataWatcher(ctx, cfg.Verify, taskMailbox, registry)
+			cancel()
+		}
+	}
+	if taskMetadataErr != nil {
+		logger.Error("Failed to set up task metadata, rejecting all tasks", zap.Error(taskMetadataErr))
+	}
+
+	// The journal and the divergence alarm only observe results, so tasks are
+	// still handled if they can't be set up.
+	var journal *divergence.Journal
+	var alarm *divergence.Alarm
+	if cfg.JournalFile != "" {
+		if journal, err = divergence.OpenJournal(cfg.JournalFile, cfg.JournalSize); err != nil {
+			logger.Error("Failed to open the result journal, results are not journaled", zap.Error(err))
+		} else if mailboxErr != nil {
+			logger.Warn("TaskMailbox unavailable, results are journaled but not compared with certified results", zap.Error(mailboxErr))
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), mailboxTimeout)
+			alarm, err = newDivergenceAlarm(ctx, cfg, journal, taskMailbox, l2Client, registry)
+			cancel()
+			if err != nil {
+				logger.Error("Failed to set up the divergence alarm, results are journaled but not compared with certified results", zap.Error(err))
+			}
+		}
+	}
+
+	tw := &TaskWorker{
+		logger:          logger,
+		contractStore:   contractStore,
+		l1Client:        l1Client,
+		l2Client:        l2Client,
+		contracts:       registry,
+		taskMailbox:     taskMailbox,
+		verifier:        verifier,
+		verifierErr:     verifierErr,
+		sla:             cfg.SLA,
+		fees:            fees,
+		feesErr:         feesErr,
+		policy:          policy,
+		policyFile:      cfg.PolicyFile,
+		policyErr:       policyErr,
+		admitted:        newAdmittedTasks(),
+		audit:           logger.Named("audit"),
+		taskMetadata:    taskMetadata,
+		taskMetadataErr: taskMetadataErr,
+		journal:         journal,
+		divergenceAlarm: alarm,
+		limits:          *limits,
+		taskLimiter:     limiter.NewTaskLimiter(limits.Defaults, limits.Handlers),
+		clock:           clock,
+		maxQueueWait:    serverTimeout,
 	}
+
+	// Apply the task metadata before the first task arrives. If it can't be
+	// read yet, the Performer's own config applies until the watcher reads it.
+	if taskMetadata != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), mailboxTimeout)
+		tw.loadTaskMetadata(ctx)
+		cancel()
+	}
+	if alarm != nil {
+		alarm.OnDivergence = tw.reportDivergence
+	}
+	return tw
+}
+
+// loadContractStore merges the executor's contract environment variables with
+// the deploy outputs selected by cfg. If the deploy outputs can't be read, it
+// returns the environment variables alone along with the error.
+func loadContractStore(cfg deployments.Config) (*deployments.Store, error) {
+	var env deployments.Source
+	envStore, envErr := contracts.NewContractStore()
+	if envErr == nil {
+		env = envStore
+	}
+
+	store, err := deployments.Load(cfg, env)
+	if err != nil {
+		fallback, _ := deployments.Load(deployments.Config{}, env)
+		return fallback, errors.Join(envErr, err)
+	}
+	return store, envErr
+}
+
+// backend returns client as a bind.ContractBackend, or nil when there is no
+// client, so that the registry sees the chain as unavailable.
+func backend(client *ethclient.Client) bind.ContractBackend {
+	if client == nil {
+		return nil
+	}
+	return client
+}
+
+// orTransport returns transport, or fallback if transport is nil.
+func orTransport(transport http.RoundTripper, fallback http.RoundTripper) http.RoundTripper {
+	if transport != nil {
+		return transport
+	}
+	return fallback
+}
+
+// now returns the current time from the worker's clock.
+func (tw *TaskWorker) now() time.Time {
+	return tw.clock()
 }
 
-func (tw *TaskWorker) ValidateTask(t *performerV1.TaskRequest) error {
+func (tw *TaskWorker) ValidateTask(t *performerV1.TaskRequest) (err error) {
+	// A panic while validating fails this task only
+	defer recovery.Recover(tw.logger, t.GetTaskId(), &err)
+
 	tw.logger.Sugar().Infow("Validating task",
 		zap.Any("task", t),
 	)
 
+	// Reject tasks that were not created in the TaskMailbox for this AVS
+	// (enabled with PERFORMER_VERIFY_TASKS) or that can't finish before their
+	// deadline.
+	task, err := tw.lookupTask(t)
+	if err != nil {
+		return err
+	}
+	if err := tw.verifyTask(t, task); err != nil {
+		return err
+	}
+	if err := tw.admitTask(t, task); err != nil {
+		return err
+	}
+
 	// ------------------------------------------------------------------------
 	// Implement your AVS task validation logic here
 	// ------------------------------------------------------------------------
@@ -75,7 +429,83 @@ func (tw *TaskWorker) ValidateTask(t *performerV1.TaskRequest) error {
 	return nil
 }
 
-func (tw *TaskWorker) HandleTask(t *performerV1.TaskRequest) (*performerV1.TaskResponse, error) {
+// payloadDeadline returns a deadline carried in the task payload, for AVSs
+// that include one. It is used when the task's TaskMailbox entry is not
+// available.
+func (tw *TaskWorker) payloadDeadline(t *performerV1.TaskRequest) (time.Time, bool) {
+	// ------------------------------------------------------------------------
+	// Decode a deadline from your payload here
+	// ------------------------------------------------------------------------
+	return time.Time{}, false
+}
+
+// validateContracts catches addresses bound to the wrong chain before the
+// first task arrives. Contracts stay usable, so a failed check is only
+// reported. It reads code at the latest block, so main runs it once rather
+// than NewTaskWorkerWithConfig, which tools such as simulate and replay call
+// for every operator and task.
+func (tw *TaskWorker) validateContracts(ctx context.Context) {
+	if tw.contractStore == nil || (tw.l1Client == nil && tw.l2Client == nil) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := tw.contracts.Validate(ctx); err != nil {
+		tw.logger.Warn("Contract registry validation failed", zap.Error(err))
+	}
+}
+
+// Close releases the worker's RPC clients and journal. Tools that build a
+// worker per task, such as replay, close each one when its task is done.
+func (tw *TaskWorker) Close() error {
+	if tw.l1Client != nil {
+		tw.l1Client.Close()
+	}
+	if tw.l2Client != nil {
+		tw.l2Client.Close()
+	}
+	if tw.journal != nil {
+		return tw.journal.Close()
+	}
+	return nil
+}
+
+func (tw *TaskWorker) HandleTask(t *performerV1.TaskRequest) (resp *performerV1.TaskResponse, err error) {
+	// A panic while handling fails this task only; the Performer keeps serving
+	// other tasks.
+	defer recovery.Recover(tw.logger, t.GetTaskId(), &err)
+
+	// ctx expires when the task's remaining SLA runs out, if ValidateTask found
+	// a deadline. Pass it to RPC calls and other long-running work.
+	ctx := context.Background()
+	admitted, _ := tw.admitted.take(t.GetTaskId())
+	if !admitted.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, admitted.deadline)
+		defer cancel()
+	}
+	defer tw.recordCompute(admitted, time.Now())
+	defer func() {
+		// resp is nil if the handler panicked
+		if err == nil && resp != nil {
+			tw.recordResult(t, resp.Result)
+		}
+	}()
+
+	// Reserve an execution slot; tasks are rejected with a ResourceExhausted error
+	// once the handler's concurrency limit and queue are both full. The server
+	// gives up on the request after serverTimeout, so never queue for longer
+	// than that: an abandoned task would keep its queue slot.
+	acquireCtx, cancelAcquire := context.WithTimeout(ctx, tw.maxQueueWait)
+	release, err := tw.taskLimiter.Acquire(acquireCtx, defaultHandler)
+	cancelAcquire()
+	if err != nil {
+		metrics.TasksRejected.Inc(1)
+		tw.logger.Warn("Rejecting task", zap.Binary("taskId", t.TaskId), zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
 	tw.logger.Sugar().Infow("Handling task",
 		zap.Any("task", t),
 	)
@@ -84,37 +514,24 @@ func (tw *TaskWorker) HandleTask(t *performerV1.TaskRequest) (*performerV1.TaskR
 	// Example: How to interact with contracts
 	// ------------------------------------------------------------------------
 
-	// Example 1: Generate bindings to contracts
-	if tw.contractStore != nil {
-
-		taskRegistrarAddr, err := tw.contractStore.GetTaskAVSRegistrar()
-		if err != nil {
-			tw.logger.Warn("TaskAVSRegistrar not found", zap.Error(err))
-		} else {
-			tw.logger.Info("TaskAVSRegistrar", zap.String("address", taskRegistrarAddr.Hex()))
-
-			// TaskAVSRegistrar contract binding
-			if tw.l1Client != nil {
-				registrar, err := taskavsregistrar.NewTaskAVSRegistrar(taskRegistrarAddr, tw.l1Client)
-				if err == nil {
-					// Call the registrar contract
-					_ = registrar
-				}
-			}
-		}
-
-		// Example 2: Get custom contract addresses
-		if helloWorldL1, err := tw.contractStore.GetContract("HELLO_WORLD_L1"); err == nil {
-			tw.logger.Info("HelloWorldL1 contract", zap.String("address", helloWorldL1.Hex()))
+	// Example 1: Get a typed binding from the contract registry. The registry
+	// knows which chain each contract is deployed on and connects the binding
+	// to that chain's client.
+	if registrar, err := tw.contracts.TaskAVSRegistrar(); err != nil {
+		tw.logger.Warn("TaskAVSRegistrar not available", zap.Error(err))
+	} else {
+		// Call the registrar contract
+		_ = registrar
+	}
 
-			// Use the address to create a contract binding
-			contract, err := helloworldl1.NewHelloWorldL1(helloWorldL1, tw.l1Client)
-			if err == nil {
-				message, _ := contract.GetMessage(nil)
-				tw.logger.Info("Contract message", zap.String("message", message))
-			}
-		}
+	// Example 2: Call a custom contract. Handler work must use ctx, so that
+	// calls stop when the task's deadline passes.
+	if contract, err := tw.contracts.HelloWorldL1(); err == nil {
+		message, _ := contract.GetMessage(&bind.CallOpts{Context: ctx})
+		tw.logger.Info("Contract message", zap.String("message", message))
+	}
 
+	if tw.contractStore != nil {
 		// Example 3: List available contracts
 		tw.logger.Info("Available contracts", zap.Strings("contracts", tw.contractStore.ListContracts()))
 	}
@@ -132,14 +549,37 @@ func (tw *TaskWorker) HandleTask(t *performerV1.TaskRequest) (*performerV1.TaskR
 }
 
 func main() {
+	// Subcommands such as `performer exec` run and exit here
+	if runCommand(os.Args[1:]) {
+		return
+	}
+
 	ctx := context.Background()
 	l, _ := zap.NewProduction()
 
 	w := NewTaskWorker(l)
+	w.validateContracts(ctx)
+
+	// Expose Prometheus metrics if a port is configured
+	if metricsPort := os.Getenv("PERFORMER_METRICS_PORT"); metricsPort != "" {
+		go func() {
+			if err := metrics.Serve(ctx, ":"+metricsPort); err != nil {
+				l.Error("Metrics server failed", zap.Error(err))
+			}
+		}()
+	}
+
+	// Reload the creator policy when its file changes, and apply changes to
+	// the on-chain task metadata
+	go w.watchPolicy(ctx, envInterval(l, "PERFORMER_POLICY_RELOAD_INTERVAL", defaultPolicyReloadInterval))
+	go w.watchTaskMetadata(ctx, envInterval(l, "PERFORMER_TASK_METADATA_INTERVAL", defaultTaskMetadataInterval))
+
+	// Compare journaled results with the certified ones
+	go w.watchDivergence(ctx, envInterval(l, "PERFORMER_DIVERGENCE_INTERVAL", defaultDivergenceInterval))
 
 	pp, err := server.NewPonosPerformerWithRpcServer(&server.PonosPerformerConfig{
 		Port:    8080,
-		Timeout: 5 * time.Second,
+		Timeout: serverTimeout,
 	}, w, l)
 	if err != nil {
 		panic(fmt.Errorf("failed to create performer: %w", err))
@@ -149,3 +589,18 @@ func main() {
 		panic(err)
 	}
 }
+
+// envInterval reads a positive duration from the environment variable name,
+// falling back to def.
+func envInterval(logger *zap.Logger, name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		logger.Warn("Invalid "+name+", using the default", zap.String("value", v))
+		return def
+	}
+	return d
+}
@@ -1,9 +1,28 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/admission"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/consensus"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/deployments"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/limiter"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/taskmetadata"
 	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"go.uber.org/zap"
 )
 
@@ -36,3 +55,231 @@ func Test_TaskRequestPayload(t *testing.T) {
 
 	t.Logf("Response: %v", resp)
 }
+
+func Test_TaskVerificationFailsClosed(t *testing.T) {
+	// Verification is enabled but there is no L2 client to reach the
+	// TaskMailbox, so tasks must be rejected rather than handled unverified.
+	cfg := &TaskWorkerConfig{Verify: mailbox.VerifierConfig{Enabled: true}}
+	taskWorker := NewTaskWorkerWithConfig(zap.NewNop(), cfg)
+
+	err := taskWorker.ValidateTask(&performerV1.TaskRequest{
+		TaskId:  []byte("test-task-id"),
+		Payload: []byte("test-data"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "task verification is unavailable") {
+		t.Fatalf("expected verification to reject the task, got %v", err)
+	}
+}
+
+func Test_TaskDeadlineAdmission(t *testing.T) {
+	cfg := &TaskWorkerConfig{SLA: admission.SLAConfig{Default: 30 * time.Second}}
+	taskWorker := NewTaskWorkerWithConfig(zap.NewNop(), cfg)
+
+	onChainTask := func(sla int64) *mailbox.Task {
+		return &mailbox.Task{
+			CreationTime: big.NewInt(time.Now().Unix()),
+			Status:       uint8(mailbox.TaskStatusCreated),
+			ExecutorOperatorSetTaskConfig: mailbox.ExecutorOperatorSetTaskConfig{
+				TaskSLA: big.NewInt(sla),
+			},
+		}
+	}
+
+	late := &performerV1.TaskRequest{TaskId: []byte("late-task")}
+	if err := taskWorker.admitTask(late, onChainTask(10)); !errors.Is(err, admission.ErrDeadline) {
+		t.Fatalf("expected a task with 10s left to be rejected, got %v", err)
+	}
+
+	onTime := &performerV1.TaskRequest{TaskId: []byte("on-time-task")}
+	if err := taskWorker.admitTask(onTime, onChainTask(120)); err != nil {
+		t.Fatalf("admitTask: %v", err)
+	}
+	admitted, ok := taskWorker.admitted.take(onTime.TaskId)
+	if !ok || time.Until(admitted.deadline) < 100*time.Second {
+		t.Fatalf("expected HandleTask to get the remaining SLA, got %s, %v", admitted.deadline, ok)
+	}
+	if _, ok := taskWorker.admitted.take(onTime.TaskId); ok {
+		t.Errorf("expected the deadline to be handed over only once")
+	}
+}
+
+func Test_CreatorPolicy(t *testing.T) {
+	spammer := common.HexToAddress("0x5a0000000000000000000000000000000000dead")
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(policyFile, []byte(`{"deny": ["`+spammer.Hex()+`"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	taskWorker := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{PolicyFile: policyFile})
+
+	task := &mailbox.Task{Creator: spammer, Status: uint8(mailbox.TaskStatusCreated)}
+	req := &performerV1.TaskRequest{TaskId: []byte("spam")}
+	if err := taskWorker.admitTask(req, task); !errors.Is(err, admission.ErrPolicyRejected) {
+		t.Fatalf("expected the spammer to be denied, got %v", err)
+	}
+
+	task.Creator = common.HexToAddress("0x0000000000000000000000000000000000000001")
+	if err := taskWorker.admitTask(req, task); err != nil {
+		t.Fatalf("admitTask: %v", err)
+	}
+
+	// Without TaskMailbox info the creator is unknown, so the task is rejected.
+	if err := taskWorker.admitTask(req, nil); !errors.Is(err, admission.ErrPolicyRejected) {
+		t.Fatalf("expected a task with an unknown creator to be rejected, got %v", err)
+	}
+}
+
+func Test_LateTasksDontUseQuota(t *testing.T) {
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(policyFile, []byte(`{"defaultQuota": {"tasksPerMinute": 1}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	taskWorker := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{
+		PolicyFile: policyFile,
+		SLA:        admission.SLAConfig{Default: 30 * time.Second},
+	})
+
+	task := func(sla int64) *mailbox.Task {
+		return &mailbox.Task{
+			Creator:      common.HexToAddress("0x0000000000000000000000000000000000000001"),
+			CreationTime: big.NewInt(time.Now().Unix()),
+			Status:       uint8(mailbox.TaskStatusCreated),
+			ExecutorOperatorSetTaskConfig: mailbox.ExecutorOperatorSetTaskConfig{
+				TaskSLA: big.NewInt(sla),
+			},
+		}
+	}
+
+	if err := taskWorker.admitTask(&performerV1.TaskRequest{TaskId: []byte("late")}, task(10)); !errors.Is(err, admission.ErrDeadline) {
+		t.Fatalf("expected the late task to be rejected, got %v", err)
+	}
+	if err := taskWorker.admitTask(&performerV1.TaskRequest{TaskId: []byte("on-time")}, task(120)); err != nil {
+		t.Fatalf("expected the late task not to count towards tasks per minute, got %v", err)
+	}
+}
+
+func Test_FeeChecksFailClosed(t *testing.T) {
+	// Fee checks need an L2 RPC URL to read the fee token's decimals.
+	cfg := &TaskWorkerConfig{Fees: admission.FeeConfig{Default: big.NewRat(1, 1)}}
+	taskWorker := NewTaskWorkerWithConfig(zap.NewNop(), cfg)
+
+	task := &mailbox.Task{AvsFee: big.NewInt(1e18), Status: uint8(mailbox.TaskStatusCreated)}
+	if err := taskWorker.admitTask(&performerV1.TaskRequest{TaskId: []byte("paid")}, task); err == nil {
+		t.Fatal("expected the task to be rejected when fees can't be checked")
+	}
+}
+
+type staticTaskConfig struct {
+	metadata []byte
+}
+
+func (s *staticTaskConfig) GetExecutorOperatorSetTaskConfig(context.Context, mailbox.OperatorSet) (*mailbox.ExecutorOperatorSetTaskConfig, error) {
+	return &mailbox.ExecutorOperatorSetTaskConfig{TaskMetadata: s.metadata}, nil
+}
+
+func Test_TaskMetadataDisablesHandlers(t *testing.T) {
+	taskWorker := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{})
+	reader := &staticTaskConfig{metadata: []byte(`{"version": 1, "handlers": {"default": {"disabled": true}}}`)}
+	taskWorker.taskMetadata = taskmetadata.NewWatcher(reader, mailbox.OperatorSet{Id: 1})
+	taskWorker.loadTaskMetadata(context.Background())
+
+	req := &performerV1.TaskRequest{TaskId: []byte("task")}
+	if err := taskWorker.ValidateTask(req); !errors.Is(err, taskmetadata.ErrHandlerDisabled) {
+		t.Fatalf("expected the disabled handler to reject the task, got %v", err)
+	}
+
+	// Re-enabling the handler applies on the next load
+	reader.metadata = []byte(`{"version": 1, "handlers": {"default": {"maxConcurrent": 1}}}`)
+	taskWorker.loadTaskMetadata(context.Background())
+	if err := taskWorker.ValidateTask(req); err != nil {
+		t.Fatalf("ValidateTask: %v", err)
+	}
+	release, err := taskWorker.taskLimiter.Acquire(context.Background(), defaultHandler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if _, err := taskWorker.taskLimiter.Acquire(context.Background(), defaultHandler); err == nil {
+		t.Error("expected the on-chain concurrency limit to apply")
+	}
+}
+
+func Test_ResultsAreJournaled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	taskWorker := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{JournalFile: path})
+	if taskWorker.journal == nil {
+		t.Fatal("expected the journal to be opened")
+	}
+	defer taskWorker.journal.Close()
+
+	taskHash := common.HexToHash("0x01")
+	resp, err := execTask(taskWorker, &performerV1.TaskRequest{TaskId: taskHash.Bytes(), Payload: []byte("data")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Task IDs that aren't task hashes can't be matched with TaskVerified
+	if _, err := execTask(taskWorker, &performerV1.TaskRequest{TaskId: []byte("task"), Payload: []byte("data")}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := taskWorker.journal.Last(10)
+	if len(entries) != 1 || entries[0].TaskHash != taskHash || entries[0].ResultHash != crypto.Keccak256Hash(resp.Result) {
+		t.Errorf("unexpected journal entries %+v", entries)
+	}
+}
+
+func Test_QueuedTasksWaitNoLongerThanTheServer(t *testing.T) {
+	taskWorker := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{
+		Limits: &limiter.Config{Defaults: limiter.Limits{MaxConcurrent: 1, MaxQueued: 1}},
+	})
+	taskWorker.maxQueueWait = 20 * time.Millisecond
+
+	release, err := taskWorker.taskLimiter.Acquire(context.Background(), defaultHandler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// No deadline and no queue timeout: the wait is still bounded
+	_, err = taskWorker.HandleTask(&performerV1.TaskRequest{TaskId: []byte("task")})
+	if !errors.Is(err, limiter.ErrResourceExhausted) {
+		t.Fatalf("expected the queued task to be rejected, got %v", err)
+	}
+}
+
+func Test_ContractsAreValidatedOnlyAtStartup(t *testing.T) {
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x60"}`, req.ID)
+	}))
+	defer rpc.Close()
+
+	dir := t.TempDir()
+	output := filepath.Join(dir, "devnet", "output")
+	if err := os.MkdirAll(output, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	deployOutput := `{"addresses": {"helloWorldL1": "0x1000000000000000000000000000000000000001"}}`
+	if err := os.WriteFile(filepath.Join(output, "deploy.json"), []byte(deployOutput), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulations and replays build a worker per operator or task behind a
+	// recording transport, so building one must not read the latest block
+	transport := &consensus.RecordingTransport{}
+	w := NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{
+		L1RpcUrl:     rpc.URL,
+		Contracts:    deployments.Config{Dir: dir, Environment: "devnet"},
+		RPCTransport: transport,
+	})
+	if calls := transport.Calls(); len(calls) != 0 {
+		t.Fatalf("Expected no RPC calls while building the worker, got %v", calls)
+	}
+
+	w.validateContracts(context.Background())
+	if calls := transport.Calls(); len(calls) != 1 || calls[0].Method != "eth_getCode" {
+		t.Errorf("Expected startup validation to read the contract's code, got %v", calls)
+	}
+}
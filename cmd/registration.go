@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/bn254"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/operator"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// runRegistration registers an operator with the AVS, deregisters it, or
+// reports its registration status:
+//
+//	performer registration status --operator-set-ids 1
+//	performer registration register --operator-set-ids 1 --socket executor:9090 [--dry-run]
+//	performer registration deregister --operator-set-ids 1
+func runRegistration(args []string) error {
+	if len(args) == 0 || (args[0] != "status" && args[0] != "register" && args[0] != "deregister") {
+		return fmt.Errorf("usage: performer registration status|register|deregister [flags]")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("registration "+action, flag.ContinueOnError)
+	chain := addChainFlags(fs, "L1_RPC_URL")
+	registrarFlag := fs.String("registrar", "", "TaskAVSRegistrar address (default: from the contract store)")
+	operatorFlag := fs.String("operator", "", "Operator address (default: the signer's address)")
+	setIds := fs.String("operator-set-ids", os.Getenv("OPERATOR_SET_IDS"), "Comma separated operator set IDs, e.g. 1 (default $OPERATOR_SET_IDS)")
+	socket := fs.String("socket", "", "Socket to register, e.g. executor.example.com:9090 (register only)")
+	ecdsaKeyFile := fs.String("ecdsa-key-file", "", "File containing the hex ECDSA signing key for ECDSA operator sets (default $ECDSA_PRIVATE_KEY, then the transaction signer)")
+	ecdsaKeystore := fs.String("ecdsa-keystore", "", "Encrypted JSON keystore holding the ECDSA signing key, unlocked with $ECDSA_KEYSTORE_PASSWORD")
+	bn254KeyFile := fs.String("bn254-key-file", "", "File containing the BN254 private key for BN254 operator sets (default $BN254_PRIVATE_KEY)")
+	dryRun := fs.Bool("dry-run", false, "Check eligibility and print the transactions without sending them")
+	output := fs.String("output", "text", "Output format: text or json")
+	timeout := fs.Duration("timeout", 5*time.Minute, "How long to wait for transactions to be mined")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	ids, err := parseOperatorSetIds(*setIds)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client, err := chain.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	registrarAddr, err := registrarAddress(*registrarFlag)
+	if err != nil {
+		return err
+	}
+	registrar, err := operator.NewRegistrar(ctx, registrarAddr, client)
+	if err != nil {
+		return err
+	}
+
+	s, signerErr := chain.signer()
+	operatorAddr := common.Address{}
+	switch {
+	case *operatorFlag != "":
+		if !common.IsHexAddress(*operatorFlag) {
+			return fmt.Errorf("invalid operator address %q", *operatorFlag)
+		}
+		operatorAddr = common.HexToAddress(*operatorFlag)
+	case signerErr == nil:
+		operatorAddr = s.Address()
+	default:
+		return fmt.Errorf("--operator is required without a configured signer: %w", signerErr)
+	}
+
+	if action == "status" {
+		status, err := registrar.Status(ctx, operatorAddr, ids)
+		if err != nil {
+			return err
+		}
+		return printRegistrationStatus(os.Stdout, *output, status)
+	}
+
+	if action == "deregister" {
+		if *dryRun {
+			status, err := registrar.Status(ctx, operatorAddr, ids)
+			if err != nil {
+				return err
+			}
+			return printRegistrationStatus(os.Stdout, *output, status)
+		}
+		if signerErr != nil {
+			return signerErr
+		}
+		tr, err := chain.transactor(ctx, client)
+		if err != nil {
+			return err
+		}
+		result, err := registrar.Deregister(ctx, tr, operatorAddr, ids)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Deregistered %s from operator sets %v in %s (block %d); OperatorDeregistered event confirmed.\n",
+			result.Operator, result.OperatorSetIds, result.TxHash, result.Block)
+		return nil
+	}
+
+	// register
+	var keys operator.Keys
+	if keys.ECDSA, keys.ECDSASource, err = loadECDSAKey(*ecdsaKeyFile, *ecdsaKeystore); err != nil {
+		return err
+	}
+	if keys.ECDSA == nil {
+		// The executor signs with its own key, which is usually not the
+		// operator's transaction key, so this is only a fallback.
+		if hs, ok := s.(operator.HashSigner); ok {
+			keys.ECDSA, keys.ECDSASource = hs, "transaction signer (no --ecdsa-key-file given)"
+		}
+	}
+	if keys.BN254, err = loadBN254Key(*bn254KeyFile); err != nil {
+		return err
+	}
+
+	plan, err := registrar.PlanRegistration(ctx, operatorAddr, ids, *socket, keys)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+
+	if signerErr != nil {
+		return signerErr
+	}
+	tr, err := chain.transactor(ctx, client)
+	if err != nil {
+		return err
+	}
+	result, err := registrar.Register(ctx, tr, operatorAddr, plan)
+	if err != nil {
+		return err
+	}
+	for _, reg := range plan.KeyRegistrations {
+		if reg.KeySource != "" {
+			fmt.Fprintf(os.Stderr, "Registering %s key %s for operator set %d (source: %s)\n", reg.Curve, reg.Pubkey, reg.OperatorSetId, reg.KeySource)
+		}
+	}
+	for _, h := range result.KeyTxHashes {
+		fmt.Fprintf(os.Stderr, "Registered key in %s\n", h)
+	}
+	fmt.Fprintf(os.Stderr, "Registered %s for operator sets %v in %s (block %d); OperatorRegistered event confirmed.\n",
+		result.Operator, result.OperatorSetIds, result.TxHash, result.Block)
+	return nil
+}
+
+func parseOperatorSetIds(s string) ([]uint32, error) {
+	var ids []uint32
+	for _, v := range splitList(s) {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operator set ID %q", v)
+		}
+		ids = append(ids, uint32(id))
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("--operator-set-ids is required")
+	}
+	return ids, nil
+}
+
+// loadECDSAKey loads the ECDSA signing key from keyFile, from keystore, or
+// from $ECDSA_PRIVATE_KEY, in that order, and describes where it came from.
+// It returns nil if none is set.
+func loadECDSAKey(keyFile, keystore string) (operator.HashSigner, string, error) {
+	switch {
+	case keyFile != "" && keystore != "":
+		return nil, "", fmt.Errorf("set only one of --ecdsa-key-file and --ecdsa-keystore")
+	case keyFile != "":
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read ECDSA key: %w", err)
+		}
+		key, err := signer.NewPrivateKeySigner(string(data))
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid ECDSA key in %s: %w", keyFile, err)
+		}
+		return key, "key file " + keyFile, nil
+	case keystore != "":
+		key, err := signer.NewKeystoreSigner(keystore, os.Getenv("ECDSA_KEYSTORE_PASSWORD"))
+		if err != nil {
+			return nil, "", err
+		}
+		return key, "keystore " + keystore, nil
+	}
+	if hexKey := strings.TrimSpace(os.Getenv("ECDSA_PRIVATE_KEY")); hexKey != "" {
+		key, err := signer.NewPrivateKeySigner(hexKey)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid $ECDSA_PRIVATE_KEY: %w", err)
+		}
+		return key, "$ECDSA_PRIVATE_KEY", nil
+	}
+	return nil, "", nil
+}
+
+// loadBN254Key reads the BN254 key from path, or from $BN254_PRIVATE_KEY if
+// path is empty. It returns nil if neither is set.
+func loadBN254Key(path string) (*bn254.PrivateKey, error) {
+	key := os.Getenv("BN254_PRIVATE_KEY")
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read BN254 key: %w", err)
+		}
+		key = string(data)
+	}
+	if key = strings.TrimSpace(key); key == "" {
+		return nil, nil
+	}
+	return bn254.NewPrivateKey(key)
+}
+
+func printRegistrationStatus(out io.Writer, format string, s *operator.Status) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	case "text":
+		fmt.Fprintf(out, "Operator: %s\n", s.Operator)
+		fmt.Fprintf(out, "AVS:      %s\n", s.Avs)
+		fmt.Fprintf(out, "Socket:   %s\n\n", s.Socket)
+		fmt.Fprintf(out, "  %-6s %-6s %-8s %-15s %s\n", "SET", "CURVE", "ALLOWED", "KEY REGISTERED", "REGISTERED")
+		for _, set := range s.Sets {
+			fmt.Fprintf(out, "  %-6d %-6s %-8t %-15t %t\n", set.OperatorSetId, set.Curve, set.Allowed, set.KeyRegistered, set.Registered)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_LoadECDSAKey(t *testing.T) {
+	t.Setenv("ECDSA_PRIVATE_KEY", "")
+
+	key, source, err := loadECDSAKey("", "")
+	if err != nil || key != nil || source != "" {
+		t.Fatalf("expected no key without a flag or $ECDSA_PRIVATE_KEY, got %v %q %v", key, source, err)
+	}
+
+	// Anvil's first dev account
+	const hexKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	want := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	path := filepath.Join(t.TempDir(), "ecdsa.key")
+	if err := os.WriteFile(path, []byte(hexKey+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	key, source, err = loadECDSAKey(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Address() != want || source != "key file "+path {
+		t.Errorf("got %s from %q, want %s from the key file", key.Address(), source, want)
+	}
+
+	t.Setenv("ECDSA_PRIVATE_KEY", hexKey)
+	if key, source, err = loadECDSAKey("", ""); err != nil || key.Address() != want || source != "$ECDSA_PRIVATE_KEY" {
+		t.Errorf("got %v from %q (%v), want %s from $ECDSA_PRIVATE_KEY", key, source, err, want)
+	}
+
+	if _, _, err := loadECDSAKey(path, "keystore.json"); err == nil {
+		t.Error("expected an error when both a key file and a keystore are set")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/consensus"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/limiter"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/replay"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// runReplay re-runs the AVS's historical tasks through the local TaskWorker
+// and reports the ones whose result differs from the certified one:
+//
+//	performer replay --from-block 1000 [--to-block 2000]
+//	performer replay --from-block 1000 --export tasks.json
+//	performer replay --fixtures tasks.json
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	l1RpcUrl := fs.String("l1-rpc-url", os.Getenv("L1_RPC_URL"), "L1 RPC URL (default $L1_RPC_URL)")
+	l2RpcUrl := fs.String("l2-rpc-url", os.Getenv("L2_RPC_URL"), "L2 RPC URL (default $L2_RPC_URL)")
+	mailboxFlag := fs.String("mailbox", "", "TaskMailbox address (default: from the contract store)")
+	avsFlag := fs.String("avs", os.Getenv("AVS_ADDRESS"), "AVS whose tasks are replayed (default $AVS_ADDRESS)")
+	fromBlock := fs.Uint64("from-block", 0, "First L2 block to scan for tasks")
+	toBlock := fs.Uint64("to-block", 0, "Last L2 block to scan for tasks (default: the latest block)")
+	chunkSize := fs.Uint64("chunk-size", replay.DefaultChunkSize, "Blocks per eth_getLogs call")
+	fixtures := fs.String("fixtures", "", "Replay the tasks in this fixtures file instead of scanning the chain")
+	export := fs.String("export", "", "Write the scanned tasks to this fixtures file instead of replaying them")
+	output := fs.String("output", "text", "Output format: text or json")
+	timeout := fs.Duration("timeout", 10*time.Minute, "How long to wait for the scan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fixtures != "" && *export != "" {
+		return fmt.Errorf("--fixtures and --export can't be used together")
+	}
+
+	var f *replay.Fixtures
+	var err error
+	if *fixtures != "" {
+		if f, err = replay.LoadFixtures(*fixtures); err != nil {
+			return err
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		if f, err = scanTasks(ctx, *l1RpcUrl, *l2RpcUrl, *mailboxFlag, *avsFlag, *fromBlock, *toBlock, *chunkSize); err != nil {
+			return err
+		}
+	}
+
+	if *export != "" {
+		if err := f.Save(*export); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d tasks from blocks %d-%d to %s\n", len(f.Tasks), f.FromBlock, f.ToBlock, *export)
+		return nil
+	}
+
+	r := &replay.Replayer{
+		Factory: func(task replay.Task, clock func() time.Time, l1 http.RoundTripper, l2 http.RoundTripper) (consensus.Performer, error) {
+			// Each task gets its own worker with pinned reads and clock, and
+			// no limits. The replayer closes it, and its clients, after the
+			// task.
+			return NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{
+				L1RpcUrl:       *l1RpcUrl,
+				L2RpcUrl:       *l2RpcUrl,
+				Limits:         &limiter.Config{},
+				Clock:          clock,
+				L1RPCTransport: l1,
+				L2RPCTransport: l2,
+			}), nil
+		},
+	}
+	report, err := r.Run(f.Tasks)
+	if err != nil {
+		return err
+	}
+	if err := printReplay(os.Stdout, *output, report); err != nil {
+		return err
+	}
+	if report.Changed() {
+		return fmt.Errorf("%d of %d certified tasks would now produce a different result", report.Diverged+report.Failed, report.Tasks-report.Unverified)
+	}
+	return nil
+}
+
+// scanTasks reads the tasks of the AVS created between from and to from the
+// TaskMailbox. L1 blocks are looked up if an L1 RPC URL is given.
+func scanTasks(ctx context.Context, l1RpcUrl string, l2RpcUrl string, mailboxFlag string, avsFlag string, from uint64, to uint64, chunkSize uint64) (*replay.Fixtures, error) {
+	if l2RpcUrl == "" {
+		return nil, fmt.Errorf("--l2-rpc-url is required to scan for tasks")
+	}
+	if !common.IsHexAddress(avsFlag) {
+		return nil, fmt.Errorf("--avs is required to scan for tasks, got %q", avsFlag)
+	}
+	avs := common.HexToAddress(avsFlag)
+
+	l2Client, err := ethclient.DialContext(ctx, l2RpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", l2RpcUrl, err)
+	}
+	defer l2Client.Close()
+
+	mailboxAddr, err := contractAddress("mailbox", mailboxFlag, taskMailboxEnvName)
+	if err != nil {
+		return nil, err
+	}
+	taskMailbox, err := mailbox.NewTaskMailbox(mailboxAddr, l2Client)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == 0 {
+		if to, err = l2Client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to read the latest L2 block: %w", err)
+		}
+	}
+
+	scanner := &replay.Scanner{Events: taskMailbox, L2Headers: l2Client, ChunkSize: chunkSize}
+	if l1RpcUrl != "" {
+		l1Client, err := ethclient.DialContext(ctx, l1RpcUrl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", l1RpcUrl, err)
+		}
+		defer l1Client.Close()
+		scanner.L1Headers = l1Client
+	}
+
+	tasks, err := scanner.Scan(ctx, avs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &replay.Fixtures{Mailbox: mailboxAddr, Avs: avs, FromBlock: from, ToBlock: to, Tasks: tasks}, nil
+}
+
+func printReplay(out io.Writer, format string, r *replay.Report) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "text":
+		for _, o := range r.Outcomes {
+			fmt.Fprintf(out, "  %s  block %-10d %-10s", o.TaskHash, o.Block, o.Status)
+			switch o.Status {
+			case replay.StatusDiverged:
+				fmt.Fprintf(out, " certified %s, now %s", o.CertifiedResult, o.Result)
+			case replay.StatusFailed, replay.StatusUnverified:
+				if o.Error != "" {
+					fmt.Fprintf(out, " %s", o.Error)
+				}
+			}
+			if o.L1Unpinned {
+				fmt.Fprint(out, " (L1 reads unpinned)")
+			}
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "\nReplayed %d tasks: %d matched, %d diverged, %d failed, %d unverified.\n",
+			r.Tasks, r.Matched, r.Diverged, r.Failed, r.Unverified)
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
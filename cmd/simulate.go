@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/consensus"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/limiter"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/payload"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+// runSimulate runs a task through several independent TaskWorkers, as if
+// executed by different operators, and reports whether their results agree.
+func runSimulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	payloadFile := fs.String("payload", "-", "Payload file, or - to read from stdin")
+	format := fs.String("format", envOr("PERFORMER_PAYLOAD_FORMAT", "hex"), "Payload format: hex, raw, json or abi")
+	abiTypes := fs.String("abi", os.Getenv("PERFORMER_PAYLOAD_ABI"), "Solidity types for --format abi, e.g. \"(uint256,string)\"")
+	taskId := fs.String("task-id", "", "Hex task ID (default: keccak256 of the payload)")
+	operatorCount := fs.Int("operators", 3, "Number of simulated operators")
+	l1RpcUrls := fs.String("l1-rpc-urls", os.Getenv("L1_RPC_URL"), "Comma separated L1 RPC URLs, assigned to operators round-robin")
+	l2RpcUrls := fs.String("l2-rpc-urls", os.Getenv("L2_RPC_URL"), "Comma separated L2 RPC URLs, assigned to operators round-robin")
+	clockStep := fs.Duration("clock-step", 2*time.Second, "Clock offset between consecutive operators")
+	clockSkew := fs.Duration("clock-skew", time.Hour, "Clock shift used to probe for time dependence (0 disables)")
+	repeats := fs.Int("repeats", 2, "Extra identical runs per operator used to probe for randomness")
+	output := fs.String("output", "text", "Output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	codec, err := payload.NewCodec(*format, *abiTypes)
+	if err != nil {
+		return err
+	}
+	task, err := readSingleTask(*payloadFile, *taskId, codec)
+	if err != nil {
+		return err
+	}
+
+	l1 := splitList(*l1RpcUrls)
+	l2 := splitList(*l2RpcUrls)
+	ops := make([]consensus.Operator, *operatorCount)
+	for i := range ops {
+		ops[i] = consensus.Operator{
+			Name:        fmt.Sprintf("operator-%d", i+1),
+			ClockOffset: time.Duration(i) * *clockStep,
+		}
+		if len(l1) > 0 {
+			ops[i].L1RpcUrl = l1[i%len(l1)]
+		}
+		if len(l2) > 0 {
+			ops[i].L2RpcUrl = l2[i%len(l2)]
+		}
+	}
+
+	sim := &consensus.Simulator{
+		Operators: ops,
+		Repeats:   *repeats,
+		ClockSkew: *clockSkew,
+		Factory: func(op consensus.Operator, clock func() time.Time, transport http.RoundTripper) (consensus.Performer, error) {
+			// Each operator gets its own worker, clients and clock, and no
+			// limits. The simulator closes it, and its clients, after each run.
+			return NewTaskWorkerWithConfig(zap.NewNop(), &TaskWorkerConfig{
+				L1RpcUrl:     op.L1RpcUrl,
+				L2RpcUrl:     op.L2RpcUrl,
+				Limits:       &limiter.Config{},
+				Clock:        clock,
+				RPCTransport: transport,
+			}), nil
+		},
+	}
+
+	report, err := sim.Run(task)
+	if err != nil {
+		return err
+	}
+	if err := printSimulation(os.Stdout, *output, hexutil.Encode(task.TaskId), report); err != nil {
+		return err
+	}
+	if !report.Agreed || len(report.Findings) > 0 {
+		return fmt.Errorf("task is not deterministic across operators")
+	}
+	return nil
+}
+
+func printSimulation(out io.Writer, format string, taskId string, r *consensus.Report) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "text":
+		fmt.Fprintf(out, "Task ID:   %s\n", taskId)
+		fmt.Fprintf(out, "Operators: %d\n\n", r.Operators)
+		for _, o := range r.Outcomes {
+			outcome := "result " + o.ResultHash.Hex()
+			if o.Error != "" {
+				outcome = "error " + o.Error
+			}
+			fmt.Fprintf(out, "  %-12s %-11s %s\n", o.Operator, o.Probe, outcome)
+		}
+		fmt.Fprintln(out)
+		if r.Agreed {
+			fmt.Fprintln(out, "Operators agree on the result.")
+		} else {
+			fmt.Fprintln(out, "Operators DISAGREE on the result; the task would not reach the signing threshold.")
+		}
+		for _, f := range r.Findings {
+			fmt.Fprintf(out, "  - %s: %s\n", f.Source, f.Detail)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
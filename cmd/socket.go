@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/taskavsregistrar"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/operator"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// runSocket shows or updates the socket an operator has registered with the
+// TaskAVSRegistrar:
+//
+//	performer socket show [--operator 0x...]
+//	performer socket set --socket executor.example.com:9090
+func runSocket(args []string) error {
+	if len(args) == 0 || (args[0] != "show" && args[0] != "set") {
+		return fmt.Errorf("usage: performer socket show|set [flags]")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("socket "+action, flag.ContinueOnError)
+	chain := addChainFlags(fs, "L1_RPC_URL")
+	registrarFlag := fs.String("registrar", "", "TaskAVSRegistrar address (default: from the contract store)")
+	operatorFlag := fs.String("operator", "", "Operator address (default: the signer's address)")
+	socket := fs.String("socket", "", "New socket, e.g. executor.example.com:9090 (set only)")
+	timeout := fs.Duration("timeout", 2*time.Minute, "How long to wait for the transaction to be mined")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client, err := chain.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	registrarAddr, err := registrarAddress(*registrarFlag)
+	if err != nil {
+		return err
+	}
+	registrar, err := taskavsregistrar.NewTaskAVSRegistrar(registrarAddr, client)
+	if err != nil {
+		return err
+	}
+
+	if action == "show" {
+		if *operatorFlag == "" {
+			// Fall back to the signer's address so operators don't have to
+			// repeat it
+			s, err := chain.signer()
+			if err != nil {
+				return fmt.Errorf("--operator is required without a configured signer: %w", err)
+			}
+			*operatorFlag = s.Address().Hex()
+		}
+		if !common.IsHexAddress(*operatorFlag) {
+			return fmt.Errorf("invalid operator address %q", *operatorFlag)
+		}
+		current, err := operator.GetSocket(ctx, registrar, common.HexToAddress(*operatorFlag))
+		if err != nil {
+			return err
+		}
+		if current == "" {
+			fmt.Fprintf(os.Stderr, "Operator %s has no socket set\n", *operatorFlag)
+			return nil
+		}
+		fmt.Println(current)
+		return nil
+	}
+
+	// Validate before touching the signer so typos fail fast
+	if err := operator.ValidateSocket(*socket); err != nil {
+		return err
+	}
+
+	tr, err := chain.transactor(ctx, client)
+	if err != nil {
+		return err
+	}
+	operatorAddr := tr.Signer().Address()
+	if *operatorFlag != "" {
+		if !common.IsHexAddress(*operatorFlag) {
+			return fmt.Errorf("invalid operator address %q", *operatorFlag)
+		}
+		// The signer must be the operator or an appointee allowed to call
+		// updateSocket on its behalf
+		operatorAddr = common.HexToAddress(*operatorFlag)
+	}
+
+	update, err := operator.UpdateSocket(ctx, registrar, tr, operatorAddr, *socket)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Operator:  %s\n", update.Operator)
+	fmt.Fprintf(os.Stderr, "Previous:  %s\n", update.Previous)
+	fmt.Fprintf(os.Stderr, "Socket:    %s\n", update.Socket)
+	fmt.Fprintf(os.Stderr, "Tx:        %s (block %d)\n", update.TxHash, update.Block)
+	fmt.Fprintln(os.Stderr, "OperatorSocketSet event confirmed.")
+	return nil
+}
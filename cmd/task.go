@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/admission"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/certificate"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/operator"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// runTask inspects tasks in the L2 TaskMailbox:
+//
+//	performer task show 0x<task hash> [--output json]
+func runTask(args []string) error {
+	if len(args) == 0 || args[0] != "show" {
+		return fmt.Errorf("usage: performer task show <task hash> [flags]")
+	}
+
+	fs := flag.NewFlagSet("task show", flag.ContinueOnError)
+	chain := &chainFlags{rpcUrl: fs.String("rpc-url", os.Getenv("L2_RPC_URL"), "L2 RPC URL (default $L2_RPC_URL)")}
+	mailboxFlag := fs.String("mailbox", "", "TaskMailbox address (default: from the contract store)")
+	format := fs.String("format", envOr("PERFORMER_PAYLOAD_FORMAT", "hex"), "Payload format used to decode the payload: hex, raw, json or abi")
+	abiTypes := fs.String("abi", os.Getenv("PERFORMER_PAYLOAD_ABI"), "Solidity types for --format abi, e.g. \"(uint256,string)\"")
+	resultFormat := fs.String("result-format", "hex", "Result format used to decode the result: hex, raw, json or abi")
+	resultABITypes := fs.String("result-abi", "", "Comma separated Solidity types for --result-format abi")
+	output := fs.String("output", "text", "Output format: text or json")
+	timeout := fs.Duration("timeout", 30*time.Second, "How long to wait for the RPC")
+
+	// Flags may come before or after the task hash
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: performer task show <task hash> [flags]")
+	}
+	hashArg := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	hashBytes, err := hexutil.Decode(hashArg)
+	if err != nil || len(hashBytes) != common.HashLength {
+		return fmt.Errorf("invalid task hash %q: expected 32 bytes of 0x-prefixed hex", hashArg)
+	}
+	hash := common.BytesToHash(hashBytes)
+
+	payloadCodec, err := payload.NewCodec(*format, *abiTypes)
+	if err != nil {
+		return err
+	}
+	resultCodec, err := payload.NewCodec(*resultFormat, *resultABITypes)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client, err := chain.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	mailboxAddr, err := contractAddress("mailbox", *mailboxFlag, taskMailboxEnvName)
+	if err != nil {
+		return err
+	}
+	taskMailbox, err := mailbox.NewTaskMailbox(mailboxAddr, client)
+	if err != nil {
+		return err
+	}
+	task, err := taskMailbox.GetTaskInfo(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("getTaskInfo failed: %w", err)
+	}
+	if task.TaskStatus() == mailbox.TaskStatusNone {
+		return fmt.Errorf("task %s does not exist in the TaskMailbox at %s", hash, mailboxAddr)
+	}
+
+	report := newTaskReport(hash, task, time.Now(), payloadCodec, resultCodec)
+
+	// Show the fee in whole tokens if the token's decimals can be read
+	if token := task.ExecutorOperatorSetTaskConfig.FeeToken; token != (common.Address{}) {
+		if reader, err := admission.NewERC20Decimals(client); err == nil {
+			if decimals, err := reader.Decimals(ctx, token); err == nil {
+				report.Fee.Amount = admission.FormatUnits(task.AvsFee, decimals)
+			}
+		}
+	}
+
+	return printTaskReport(os.Stdout, *output, report)
+}
+
+// taskReport is printed by `performer task show`.
+type taskReport struct {
+	TaskHash              common.Hash    `json:"taskHash"`
+	Status                string         `json:"status"`
+	Creator               common.Address `json:"creator"`
+	Avs                   common.Address `json:"avs"`
+	ExecutorOperatorSetId uint32         `json:"executorOperatorSetId"`
+	ReferenceTimestamp    uint32         `json:"operatorTableReferenceTimestamp"`
+
+	Timeline taskTimeline `json:"timeline"`
+	Fee      taskFee      `json:"fee"`
+	Config   taskConfig   `json:"config"`
+
+	Payload        hexutil.Bytes `json:"payload"`
+	PayloadDecoded any           `json:"payloadDecoded,omitempty"`
+	Result         hexutil.Bytes `json:"result,omitempty"`
+	ResultDecoded  any           `json:"resultDecoded,omitempty"`
+	Certificate    any           `json:"certificate,omitempty"`
+
+	// Errors lists the parts of the task that could not be decoded.
+	Errors []string `json:"errors,omitempty"`
+}
+
+type taskTimeline struct {
+	Created  time.Time `json:"created"`
+	SLA      string    `json:"sla"`
+	Deadline time.Time `json:"deadline"`
+	// Remaining is the time left before the deadline, negative once it has
+	// passed.
+	Remaining string `json:"remaining"`
+}
+
+type taskFee struct {
+	AvsFee          string         `json:"avsFee"`
+	Amount          string         `json:"amount,omitempty"`
+	Token           common.Address `json:"token"`
+	Collector       common.Address `json:"collector"`
+	FeeSplit        string         `json:"feeSplit"`
+	RefundCollector common.Address `json:"refundCollector"`
+	Refunded        bool           `json:"refunded"`
+	Refundable      bool           `json:"refundable"`
+}
+
+type taskConfig struct {
+	TaskHook     common.Address `json:"taskHook"`
+	CurveType    string         `json:"curveType"`
+	Consensus    any            `json:"consensus"`
+	TaskMetadata hexutil.Bytes  `json:"taskMetadata"`
+}
+
+// ecdsaCertificateSummary and bn254CertificateSummary are the parts of a
+// certificate worth reading in a report.
+type ecdsaCertificateSummary struct {
+	ReferenceTimestamp uint32      `json:"referenceTimestamp"`
+	MessageHash        common.Hash `json:"messageHash"`
+	Signatures         int         `json:"signatures"`
+}
+
+type bn254CertificateSummary struct {
+	ReferenceTimestamp uint32      `json:"referenceTimestamp"`
+	MessageHash        common.Hash `json:"messageHash"`
+	NonSigners         int         `json:"nonSigners"`
+}
+
+// newTaskReport describes task as of now, decoding its payload and result
+// with the given codecs.
+func newTaskReport(hash common.Hash, task *mailbox.Task, now time.Time, payloadCodec payload.Codec, resultCodec payload.Codec) *taskReport {
+	cfg := task.ExecutorOperatorSetTaskConfig
+	r := &taskReport{
+		TaskHash:              hash,
+		Status:                task.TaskStatus().String(),
+		Creator:               task.Creator,
+		Avs:                   task.Avs,
+		ExecutorOperatorSetId: task.ExecutorOperatorSetId,
+		ReferenceTimestamp:    task.OperatorTableReferenceTimestamp,
+		Fee: taskFee{
+			AvsFee:          bigString(task.AvsFee),
+			Token:           cfg.FeeToken,
+			Collector:       cfg.FeeCollector,
+			FeeSplit:        fmt.Sprintf("%.2f%%", float64(task.FeeSplit)/100),
+			RefundCollector: task.RefundCollector,
+			Refunded:        task.IsFeeRefunded,
+			Refundable:      task.TaskStatus() == mailbox.TaskStatusExpired && !task.IsFeeRefunded && task.AvsFee != nil && task.AvsFee.Sign() > 0,
+		},
+		Config: taskConfig{
+			TaskHook:     cfg.TaskHook,
+			CurveType:    operator.CurveType(cfg.CurveType).String(),
+			Consensus:    mailbox.NewTaskConfigFile(mailbox.OperatorSet{}, cfg).Consensus,
+			TaskMetadata: cfg.TaskMetadata,
+		},
+		Payload: task.Payload,
+	}
+
+	if deadline, ok := admission.TaskDeadline(task); ok {
+		created := time.Unix(task.CreationTime.Int64(), 0).UTC()
+		r.Timeline = taskTimeline{
+			Created:   created,
+			SLA:       deadline.Sub(created).String(),
+			Deadline:  deadline.UTC(),
+			Remaining: deadline.Sub(now).Truncate(time.Second).String(),
+		}
+	}
+
+	var err error
+	if r.PayloadDecoded, err = payloadCodec.Decode(task.Payload); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("failed to decode payload as %s: %v", payloadCodec.Name(), err))
+	}
+
+	if task.TaskStatus() != mailbox.TaskStatusVerified {
+		return r
+	}
+	r.Result = task.Result
+	if r.ResultDecoded, err = resultCodec.Decode(task.Result); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("failed to decode result as %s: %v", resultCodec.Name(), err))
+	}
+	switch operator.CurveType(cfg.CurveType) {
+	case operator.CurveTypeECDSA:
+		cert, err := certificate.DecodeECDSA(task.ExecutorCert)
+		if err != nil {
+			r.Errors = append(r.Errors, err.Error())
+			break
+		}
+		r.Certificate = ecdsaCertificateSummary{ReferenceTimestamp: cert.ReferenceTimestamp, MessageHash: cert.MessageHash, Signatures: len(cert.Sig) / 65}
+	case operator.CurveTypeBN254:
+		cert, err := certificate.DecodeBN254(task.ExecutorCert)
+		if err != nil {
+			r.Errors = append(r.Errors, err.Error())
+			break
+		}
+		r.Certificate = bn254CertificateSummary{ReferenceTimestamp: cert.ReferenceTimestamp, MessageHash: cert.MessageHash, NonSigners: len(cert.NonSignerWitnesses)}
+	}
+	return r
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
+
+func printTaskReport(out io.Writer, format string, r *taskReport) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "text":
+		fmt.Fprintf(out, "Task:         %s\n", r.TaskHash)
+		fmt.Fprintf(out, "Status:       %s\n", r.Status)
+		fmt.Fprintf(out, "Creator:      %s\n", r.Creator)
+		fmt.Fprintf(out, "AVS:          %s\n", r.Avs)
+		fmt.Fprintf(out, "Operator set: %d (reference timestamp %d)\n\n", r.ExecutorOperatorSetId, r.ReferenceTimestamp)
+
+		fmt.Fprintf(out, "Created:      %s\n", r.Timeline.Created.Format(time.RFC3339))
+		fmt.Fprintf(out, "Deadline:     %s (SLA %s, %s remaining)\n\n", r.Timeline.Deadline.Format(time.RFC3339), r.Timeline.SLA, r.Timeline.Remaining)
+
+		fee := r.Fee.AvsFee + " base units"
+		if r.Fee.Amount != "" {
+			fee = r.Fee.Amount + " (" + fee + ")"
+		}
+		fmt.Fprintf(out, "Fee:          %s of %s\n", fee, r.Fee.Token)
+		fmt.Fprintf(out, "Fee split:    %s, collector %s\n", r.Fee.FeeSplit, r.Fee.Collector)
+		fmt.Fprintf(out, "Refund:       refunded %t, refundable %t, collector %s\n\n", r.Fee.Refunded, r.Fee.Refundable, r.Fee.RefundCollector)
+
+		consensus, err := json.Marshal(r.Config.Consensus)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Task hook:    %s\n", r.Config.TaskHook)
+		fmt.Fprintf(out, "Curve:        %s\n", r.Config.CurveType)
+		fmt.Fprintf(out, "Consensus:    %s\n", consensus)
+		fmt.Fprintf(out, "Metadata:     %s\n\n", r.Config.TaskMetadata)
+
+		fmt.Fprintf(out, "Payload:      %s\n", r.Payload)
+		if err := printDecoded(out, "Decoded:      ", r.PayloadDecoded); err != nil {
+			return err
+		}
+		if r.Status == mailbox.TaskStatusVerified.String() {
+			fmt.Fprintf(out, "Result:       %s\n", r.Result)
+			if err := printDecoded(out, "Decoded:      ", r.ResultDecoded); err != nil {
+				return err
+			}
+			if err := printDecoded(out, "Certificate:  ", r.Certificate); err != nil {
+				return err
+			}
+		}
+		for _, e := range r.Errors {
+			fmt.Fprintf(out, "Error:        %s\n", e)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
+
+func printDecoded(out io.Writer, label string, v any) error {
+	if v == nil {
+		return nil
+	}
+	decoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s%s\n", label, decoded)
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/certificate"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/operator"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/payload"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_TaskReport(t *testing.T) {
+	hexCodec, err := payload.NewCodec("hex", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawCodec, err := payload.NewCodec("raw", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := certificate.EncodeECDSA(&certificate.ECDSACertificate{ReferenceTimestamp: 7, Sig: make([]byte, 130)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Unix(1_700_000_000, 0)
+	task := &mailbox.Task{
+		Creator:      common.HexToAddress("0x01"),
+		CreationTime: big.NewInt(created.Unix()),
+		AvsFee:       big.NewInt(100),
+		FeeSplit:     2500,
+		Status:       uint8(mailbox.TaskStatusExpired),
+		Payload:      []byte("hello"),
+		Result:       []byte("world"),
+		ExecutorCert: cert,
+		ExecutorOperatorSetTaskConfig: mailbox.ExecutorOperatorSetTaskConfig{
+			TaskSLA:   big.NewInt(60),
+			CurveType: uint8(operator.CurveTypeECDSA),
+		},
+	}
+
+	r := newTaskReport(common.Hash{1}, task, created.Add(90*time.Second), rawCodec, rawCodec)
+	if r.Status != "EXPIRED" || !r.Fee.Refundable || r.Fee.FeeSplit != "25.00%" {
+		t.Errorf("unexpected report for an expired task: %+v", r)
+	}
+	if r.Timeline.SLA != "1m0s" || r.Timeline.Remaining != "-30s" {
+		t.Errorf("unexpected timeline: %+v", r.Timeline)
+	}
+	if r.PayloadDecoded != "hello" || r.Result != nil || r.Certificate != nil {
+		t.Errorf("only verified tasks should report a result: %+v", r)
+	}
+
+	task.Status = uint8(mailbox.TaskStatusVerified)
+	r = newTaskReport(common.Hash{1}, task, created, hexCodec, rawCodec)
+	if r.Fee.Refundable {
+		t.Error("a verified task should not be refundable")
+	}
+	summary, ok := r.Certificate.(ecdsaCertificateSummary)
+	if !ok || summary.Signatures != 2 || summary.ReferenceTimestamp != 7 {
+		t.Errorf("unexpected certificate: %+v", r.Certificate)
+	}
+	if r.ResultDecoded != "world" {
+		t.Errorf("ResultDecoded = %v, want world", r.ResultDecoded)
+	}
+
+	var out bytes.Buffer
+	if err := printTaskReport(&out, "json", r); err != nil {
+		t.Fatal(err)
+	}
+	var decoded taskReport
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("task output is not valid JSON: %v", err)
+	}
+	if decoded.Status != "VERIFIED" || string(decoded.Result) != "world" {
+		t.Errorf("unexpected task output: %s", out.String())
+	}
+
+	out.Reset()
+	if err := printTaskReport(&out, "text", r); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Certificate:") {
+		t.Errorf("text output is missing the certificate:\n%s", out.String())
+	}
+	if err := printTaskReport(&out, "yaml", r); err == nil {
+		t.Error("expected unknown output format to fail")
+	}
+}
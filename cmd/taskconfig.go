@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"sigs.k8s.io/yaml"
+)
+
+// taskHookEnvName is the contract store name of the AVS's task hook.
+const taskHookEnvName = "AVS_TASK_HOOK"
+
+// runTaskConfig shows the task config of an executor operator set on the L2
+// TaskMailbox, or diffs and applies a declared one:
+//
+//	performer taskconfig show --operator-set-id 1
+//	performer taskconfig diff --file taskconfig.yaml
+//	performer taskconfig apply --file taskconfig.yaml [--dry-run]
+func runTaskConfig(args []string) error {
+	if len(args) == 0 || (args[0] != "show" && args[0] != "diff" && args[0] != "apply") {
+		return fmt.Errorf("usage: performer taskconfig show|diff|apply [flags]")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("taskconfig "+action, flag.ContinueOnError)
+	chain := addChainFlags(fs, "L2_RPC_URL")
+	mailboxFlag := fs.String("mailbox", "", "TaskMailbox address (default: from the contract store)")
+	file := fs.String("file", os.Getenv("PERFORMER_TASK_CONFIG_FILE"), "Declared task config file, YAML or JSON (diff and apply; default $PERFORMER_TASK_CONFIG_FILE)")
+	avsFlag := fs.String("avs", "", "AVS address (default: the file's avs, then $AVS_ADDRESS, then the signer's address)")
+	setId := fs.String("operator-set-id", os.Getenv("EXECUTOR_OPERATOR_SET_ID"), "Executor operator set ID (show only; default $EXECUTOR_OPERATOR_SET_ID)")
+	dryRun := fs.Bool("dry-run", false, "Validate and print the changes without sending them (apply only)")
+	output := fs.String("output", "text", "Output format: text or json (show prints text as YAML)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "How long to wait for transactions to be mined")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	// Read the declared config before dialing so typos fail fast
+	var declaredFile *mailbox.TaskConfigFile
+	var declared mailbox.ExecutorOperatorSetTaskConfig
+	var set mailbox.OperatorSet
+	if action == "show" {
+		if *setId == "" {
+			return fmt.Errorf("--operator-set-id is required")
+		}
+		id, err := strconv.ParseUint(*setId, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid operator set ID %q", *setId)
+		}
+		set.Id = uint32(id)
+	} else {
+		if *file == "" {
+			return fmt.Errorf("--file is required")
+		}
+		var err error
+		if declaredFile, err = mailbox.LoadTaskConfigFile(*file); err != nil {
+			return err
+		}
+		if declared, err = declaredFile.TaskConfig(); err != nil {
+			return fmt.Errorf("invalid task config file %s: %w", *file, err)
+		}
+		set.Id = declaredFile.ExecutorOperatorSetId
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	client, err := chain.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	mailboxAddr, err := contractAddress("mailbox", *mailboxFlag, taskMailboxEnvName)
+	if err != nil {
+		return err
+	}
+	taskMailbox, err := mailbox.NewTaskMailbox(mailboxAddr, client)
+	if err != nil {
+		return err
+	}
+
+	avs := *avsFlag
+	if avs == "" && declaredFile != nil && declaredFile.Avs != nil {
+		avs = declaredFile.Avs.Hex()
+	}
+	if avs == "" {
+		avs = os.Getenv("AVS_ADDRESS")
+	}
+	if avs == "" {
+		s, err := chain.signer()
+		if err != nil {
+			return fmt.Errorf("--avs is required without a configured signer: %w", err)
+		}
+		avs = s.Address().Hex()
+	}
+	if !common.IsHexAddress(avs) {
+		return fmt.Errorf("invalid AVS address %q", avs)
+	}
+	set.Avs = common.HexToAddress(avs)
+
+	current, err := taskMailbox.GetExecutorOperatorSetTaskConfig(ctx, set)
+	if err != nil {
+		return fmt.Errorf("getExecutorOperatorSetTaskConfig failed: %w", err)
+	}
+	registered, err := taskMailbox.IsExecutorOperatorSetRegistered(ctx, set)
+	if err != nil {
+		return fmt.Errorf("isExecutorOperatorSetRegistered failed: %w", err)
+	}
+
+	if action == "show" {
+		if current.TaskHook == (common.Address{}) {
+			fmt.Fprintf(os.Stderr, "Operator set %s/%d has no task config set\n", set.Avs, set.Id)
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "Registered: %t\n", registered)
+		return printTaskConfigFile(os.Stdout, *output, mailbox.NewTaskConfigFile(set, *current))
+	}
+
+	if declared.TaskHook == (common.Address{}) {
+		if declared.TaskHook, err = contractAddress("taskHook", "", taskHookEnvName); err != nil {
+			return err
+		}
+	}
+	maxSLA, err := taskMailbox.MaxTaskSLA(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read MAX_TASK_SLA: %w", err)
+	}
+	if err := mailbox.ValidateTaskConfig(declared, maxSLA); err != nil {
+		return fmt.Errorf("invalid task config: %w", err)
+	}
+
+	plan := &taskConfigPlan{
+		Avs:                   set.Avs,
+		ExecutorOperatorSetId: set.Id,
+		Registered:            registered,
+		Changes:               mailbox.DiffTaskConfig(*current, declared),
+	}
+	if err := printTaskConfigPlan(os.Stdout, *output, plan); err != nil {
+		return err
+	}
+	if action == "diff" || *dryRun {
+		return nil
+	}
+	if len(plan.Changes) == 0 && registered {
+		fmt.Fprintln(os.Stderr, "Task config is up to date.")
+		return nil
+	}
+
+	tr, err := chain.transactor(ctx, client)
+	if err != nil {
+		return err
+	}
+	if len(plan.Changes) > 0 {
+		receipt, err := tr.SendAndWait(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return taskMailbox.SetExecutorOperatorSetTaskConfig(opts, set, declared)
+		})
+		if err != nil {
+			return fmt.Errorf("setExecutorOperatorSetTaskConfig failed: %w", err)
+		}
+		stored, err := taskMailbox.GetExecutorOperatorSetTaskConfig(ctx, set)
+		if err != nil {
+			return fmt.Errorf("failed to read back the task config: %w", err)
+		}
+		if changes := mailbox.DiffTaskConfig(*stored, declared); len(changes) > 0 {
+			return fmt.Errorf("transaction %s was mined but the stored task config still differs in %s", receipt.TxHash, changes[0].Field)
+		}
+		fmt.Fprintf(os.Stderr, "Set task config in %s (block %d)\n", receipt.TxHash, receipt.BlockNumber)
+	}
+
+	// Setting the config may register the operator set as well
+	if registered, err = taskMailbox.IsExecutorOperatorSetRegistered(ctx, set); err != nil {
+		return fmt.Errorf("isExecutorOperatorSetRegistered failed: %w", err)
+	}
+	if !registered {
+		receipt, err := tr.SendAndWait(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return taskMailbox.RegisterExecutorOperatorSet(opts, set, true)
+		})
+		if err != nil {
+			return fmt.Errorf("registerExecutorOperatorSet failed: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Registered operator set %s/%d in %s (block %d)\n", set.Avs, set.Id, receipt.TxHash, receipt.BlockNumber)
+	}
+	return nil
+}
+
+// taskConfigPlan is what `taskconfig apply` will change.
+type taskConfigPlan struct {
+	Avs                   common.Address             `json:"avs"`
+	ExecutorOperatorSetId uint32                     `json:"executorOperatorSetId"`
+	Registered            bool                       `json:"registered"`
+	Changes               []mailbox.TaskConfigChange `json:"changes"`
+}
+
+func printTaskConfigPlan(out io.Writer, format string, p *taskConfigPlan) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	case "text":
+		fmt.Fprintf(out, "Operator set: %s/%d\n", p.Avs, p.ExecutorOperatorSetId)
+		fmt.Fprintf(out, "Registered:   %t\n\n", p.Registered)
+		if len(p.Changes) == 0 {
+			fmt.Fprintln(out, "  No changes")
+			return nil
+		}
+		fmt.Fprintf(out, "  %-13s %-44s %s\n", "FIELD", "CURRENT", "DECLARED")
+		for _, c := range p.Changes {
+			fmt.Fprintf(out, "  %-13s %-44s %s\n", c.Field, c.Current, c.Declared)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
+
+// printTaskConfigFile prints f as a task config file that diff and apply
+// accept: YAML for text, or JSON.
+func printTaskConfigFile(out io.Writer, format string, f *mailbox.TaskConfigFile) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(f)
+	case "text":
+		data, err := yaml.Marshal(f)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q (expected text or json)", format)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/metrics"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/taskmetadata"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"go.uber.org/zap"
+)
+
+// defaultTaskMetadataInterval is how often the task metadata is checked for
+// changes.
+const defaultTaskMetadataInterval = 30 * time.Second
+
+// newTaskMetadataWatcher builds the watcher for the task metadata of the
+// Performer's executor operator set.
+func newTaskMetadataWatcher(ctx context.Context, cfg mailbox.VerifierConfig, taskMailbox *mailbox.TaskMailbox, registry *bindings.Registry) (*taskmetadata.Watcher, error) {
+	if cfg.ExecutorOperatorSetId == nil {
+		return nil, errors.New("task metadata needs EXECUTOR_OPERATOR_SET_ID")
+	}
+	avs, err := avsAddress(ctx, cfg.Avs, registry)
+	if err != nil {
+		return nil, err
+	}
+	return taskmetadata.NewWatcher(taskMailbox, mailbox.OperatorSet{Avs: avs, Id: *cfg.ExecutorOperatorSetId}), nil
+}
+
+// checkHandler rejects t if the task metadata disables handler.
+func (tw *TaskWorker) checkHandler(t *performerV1.TaskRequest, handler string) error {
+	if tw.taskMetadataErr != nil {
+		return fmt.Errorf("task metadata is unavailable: %w", tw.taskMetadataErr)
+	}
+	if tw.taskMetadata == nil || tw.taskMetadata.Current().Enabled(handler) {
+		return nil
+	}
+	metrics.TasksDisabled.Inc(1)
+	err := &taskmetadata.DisabledError{Handler: handler}
+	tw.logger.Warn("Rejecting task for a disabled handler", zap.Binary("taskId", t.GetTaskId()), zap.Error(err))
+	return err
+}
+
+// applyTaskMetadata applies the limits in m on top of the Performer's own.
+func (tw *TaskWorker) applyTaskMetadata(m *taskmetadata.Metadata) {
+	tw.taskLimiter.SetLimits(m.Limits(tw.limits))
+	if m == nil {
+		tw.logger.Info("Task metadata is empty, using the Performer's own config")
+		return
+	}
+	tw.logger.Info("Applied task metadata", zap.Int("version", m.Version), zap.Any("handlers", m.Handlers))
+}
+
+// loadTaskMetadata reads the task metadata once and applies it if it changed.
+func (tw *TaskWorker) loadTaskMetadata(ctx context.Context) {
+	changed, err := tw.taskMetadata.Load(ctx)
+	if err != nil {
+		tw.logger.Error("Failed to load task metadata, keeping the current config", zap.Error(err))
+		return
+	}
+	if changed {
+		tw.applyTaskMetadata(tw.taskMetadata.Current())
+	}
+}
+
+// watchTaskMetadata applies changes to the task metadata until ctx is done.
+func (tw *TaskWorker) watchTaskMetadata(ctx context.Context, interval time.Duration) {
+	if tw.taskMetadata == nil {
+		return
+	}
+	tw.taskMetadata.Watch(ctx, interval, tw.applyTaskMetadata, func(err error) {
+		tw.logger.Error("Failed to load task metadata, keeping the current config", zap.Error(err))
+	})
+}
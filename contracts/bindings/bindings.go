@@ -0,0 +1,187 @@
+// Package bindings holds the generated Go bindings for the contracts in
+// contracts/src and a registry of them. Regenerate after changing a contract
+// with `make bindings`, or check for drift with `make bindings-check`.
+package bindings
+
+//go:generate go run ../../cmd/bindgen -root ../..
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/reverts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Chain is the chain a contract is deployed to.
+type Chain string
+
+const (
+	L1 Chain = "l1"
+	L2 Chain = "l2"
+)
+
+// Entry describes a generated binding of type T.
+type Entry[T any] struct {
+	// Name is the Solidity contract name, e.g. HelloWorldL1.
+	Name string
+
+	// EnvName is the environment variable the executor template sets to the
+	// contract's deployed address, e.g. HELLO_WORLD_L1.
+	EnvName string
+
+	Chain    Chain
+	MetaData *bind.MetaData
+
+	// New binds the contract at address.
+	New func(address common.Address, backend bind.ContractBackend) (T, error)
+}
+
+// Contract returns the untyped form of e, for use in Contracts.
+func (e Entry[T]) Contract() Contract {
+	return Contract{
+		Name:     e.Name,
+		EnvName:  e.EnvName,
+		Chain:    e.Chain,
+		MetaData: e.MetaData,
+		New: func(address common.Address, backend bind.ContractBackend) (any, error) {
+			return e.New(address, backend)
+		},
+	}
+}
+
+// Contract is an Entry with the binding type erased.
+type Contract struct {
+	Name     string
+	EnvName  string
+	Chain    Chain
+	MetaData *bind.MetaData
+	New      func(address common.Address, backend bind.ContractBackend) (any, error)
+}
+
+// AddressSource resolves a contract's address from its environment variable
+// name. The Performer's contracts.ContractStore implements it.
+type AddressSource interface {
+	GetContract(name string) (common.Address, error)
+}
+
+// Registry hands out contract bindings connected to the client for the chain
+// each contract is deployed on. Bindings are created once and cached.
+type Registry struct {
+	addresses AddressSource
+	backends  map[Chain]bind.ContractBackend
+
+	mu        sync.Mutex
+	instances map[string]any
+}
+
+// NewRegistry creates a Registry. Pass a nil backend for a chain the
+// Performer has no client for; contracts on that chain are then unavailable.
+func NewRegistry(addresses AddressSource, l1 bind.ContractBackend, l2 bind.ContractBackend) *Registry {
+	backends := make(map[Chain]bind.ContractBackend)
+	if l1 != nil {
+		backends[L1] = l1
+	}
+	if l2 != nil {
+		backends[L2] = l2
+	}
+	return &Registry{
+		addresses: addresses,
+		backends:  backends,
+		instances: make(map[string]any),
+	}
+}
+
+// Get returns the binding for e, creating it on first use.
+func Get[T any](r *Registry, e Entry[T]) (T, error) {
+	var zero T
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if instance, ok := r.instances[e.EnvName]; ok {
+		return instance.(T), nil
+	}
+
+	address, backend, err := r.resolve(e.Contract())
+	if err != nil {
+		return zero, err
+	}
+	instance, err := e.New(address, backend)
+	if err != nil {
+		return zero, fmt.Errorf("failed to bind %s: %w", e.Name, err)
+	}
+	r.instances[e.EnvName] = instance
+	return instance, nil
+}
+
+func (r *Registry) resolve(c Contract) (common.Address, bind.ContractBackend, error) {
+	if r.addresses == nil {
+		return common.Address{}, nil, fmt.Errorf("%s: no contract addresses available", c.Name)
+	}
+	address, err := r.addresses.GetContract(c.EnvName)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("%s: address not configured (%s): %w", c.Name, c.EnvName, err)
+	}
+	backend, ok := r.backends[c.Chain]
+	if !ok {
+		return common.Address{}, nil, fmt.Errorf("%s is deployed on %s but there is no %s client", c.Name, c.Chain, c.Chain)
+	}
+	return address, backend, nil
+}
+
+// Validate checks every contract with a configured address: its chain must
+// have a client, and there must be code at the address on that chain, which
+// catches an L2 address bound to the L1 client. Contracts without an address
+// are skipped.
+func (r *Registry) Validate(ctx context.Context) error {
+	if r.addresses == nil {
+		return fmt.Errorf("no contract addresses available")
+	}
+
+	var errs []error
+	for _, name := range sortedContractNames() {
+		c := Contracts[name]
+		if _, err := r.addresses.GetContract(c.EnvName); err != nil {
+			continue
+		}
+		address, backend, err := r.resolve(c)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		code, err := backend.CodeAt(ctx, address, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to read code at %s on %s: %w", c.Name, address, c.Chain, err))
+			continue
+		}
+		if len(code) == 0 {
+			errs = append(errs, fmt.Errorf("%s: no contract at %s on %s", c.Name, address, c.Chain))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func init() {
+	// Decode the custom errors of every generated binding wherever their calls
+	// revert
+	for _, name := range sortedContractNames() {
+		c := Contracts[name]
+		if parsed, err := c.MetaData.GetAbi(); err == nil {
+			reverts.Register(c.Name, *parsed)
+		}
+	}
+}
+
+func sortedContractNames() []string {
+	names := make([]string, 0, len(Contracts))
+	for name := range Contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
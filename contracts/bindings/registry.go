@@ -0,0 +1,74 @@
+// Code generated by bindgen. DO NOT EDIT.
+
+package bindings
+
+import (
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/helloworldl1"
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/taskavsregistrar"
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/avstaskhook"
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l2/helloworldl2"
+)
+
+// HelloWorldL1 is the registry entry for helloworldl1.HelloWorldL1, deployed at $HELLO_WORLD_L1.
+var HelloWorldL1 = Entry[*helloworldl1.HelloWorldL1]{
+	Name:     "HelloWorldL1",
+	EnvName:  "HELLO_WORLD_L1",
+	Chain:    L1,
+	MetaData: helloworldl1.HelloWorldL1MetaData,
+	New:      helloworldl1.NewHelloWorldL1,
+}
+
+// HelloWorldL1 returns the HelloWorldL1 binding on L1.
+func (r *Registry) HelloWorldL1() (*helloworldl1.HelloWorldL1, error) {
+	return Get(r, HelloWorldL1)
+}
+
+// TaskAVSRegistrar is the registry entry for taskavsregistrar.TaskAVSRegistrar, deployed at $TASK_AVS_REGISTRAR.
+var TaskAVSRegistrar = Entry[*taskavsregistrar.TaskAVSRegistrar]{
+	Name:     "TaskAVSRegistrar",
+	EnvName:  "TASK_AVS_REGISTRAR",
+	Chain:    L1,
+	MetaData: taskavsregistrar.TaskAVSRegistrarMetaData,
+	New:      taskavsregistrar.NewTaskAVSRegistrar,
+}
+
+// TaskAVSRegistrar returns the TaskAVSRegistrar binding on L1.
+func (r *Registry) TaskAVSRegistrar() (*taskavsregistrar.TaskAVSRegistrar, error) {
+	return Get(r, TaskAVSRegistrar)
+}
+
+// AVSTaskHook is the registry entry for avstaskhook.AVSTaskHook, deployed at $AVS_TASK_HOOK.
+var AVSTaskHook = Entry[*avstaskhook.AVSTaskHook]{
+	Name:     "AVSTaskHook",
+	EnvName:  "AVS_TASK_HOOK",
+	Chain:    L2,
+	MetaData: avstaskhook.AVSTaskHookMetaData,
+	New:      avstaskhook.NewAVSTaskHook,
+}
+
+// AVSTaskHook returns the AVSTaskHook binding on L2.
+func (r *Registry) AVSTaskHook() (*avstaskhook.AVSTaskHook, error) {
+	return Get(r, AVSTaskHook)
+}
+
+// HelloWorldL2 is the registry entry for helloworldl2.HelloWorldL2, deployed at $HELLO_WORLD_L2.
+var HelloWorldL2 = Entry[*helloworldl2.HelloWorldL2]{
+	Name:     "HelloWorldL2",
+	EnvName:  "HELLO_WORLD_L2",
+	Chain:    L2,
+	MetaData: helloworldl2.HelloWorldL2MetaData,
+	New:      helloworldl2.NewHelloWorldL2,
+}
+
+// HelloWorldL2 returns the HelloWorldL2 binding on L2.
+func (r *Registry) HelloWorldL2() (*helloworldl2.HelloWorldL2, error) {
+	return Get(r, HelloWorldL2)
+}
+
+// Contracts maps each contract's environment variable name to its entry.
+var Contracts = map[string]Contract{
+	"HELLO_WORLD_L1":     HelloWorldL1.Contract(),
+	"TASK_AVS_REGISTRAR": TaskAVSRegistrar.Contract(),
+	"AVS_TASK_HOOK":      AVSTaskHook.Contract(),
+	"HELLO_WORLD_L2":     HelloWorldL2.Contract(),
+}
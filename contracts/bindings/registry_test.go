@@ -0,0 +1,133 @@
+package bindings
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/reverts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type fakeAddresses map[string]common.Address
+
+func (f fakeAddresses) GetContract(name string) (common.Address, error) {
+	address, ok := f[name]
+	if !ok {
+		return common.Address{}, fmt.Errorf("contract %s not found", name)
+	}
+	return address, nil
+}
+
+// fakeBackend reports code only at the addresses deployed on it.
+type fakeBackend struct {
+	bind.ContractBackend
+	deployed map[common.Address]bool
+}
+
+func (f *fakeBackend) CodeAt(_ context.Context, address common.Address, _ *big.Int) ([]byte, error) {
+	if f.deployed[address] {
+		return []byte{0x60, 0x80}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeBackend) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+var (
+	l1Address = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	l2Address = common.HexToAddress("0x2000000000000000000000000000000000000002")
+)
+
+func Test_RegistryCachesInstances(t *testing.T) {
+	addresses := fakeAddresses{HelloWorldL1.EnvName: l1Address}
+	registry := NewRegistry(addresses, &fakeBackend{}, nil)
+
+	first, err := registry.HelloWorldL1()
+	if err != nil {
+		t.Fatalf("HelloWorldL1: %v", err)
+	}
+	second, err := registry.HelloWorldL1()
+	if err != nil {
+		t.Fatalf("HelloWorldL1: %v", err)
+	}
+	if first != second {
+		t.Errorf("expected the cached binding to be returned")
+	}
+}
+
+func Test_RegistryRequiresChainClient(t *testing.T) {
+	addresses := fakeAddresses{HelloWorldL2.EnvName: l2Address}
+	registry := NewRegistry(addresses, &fakeBackend{}, nil)
+
+	_, err := registry.HelloWorldL2()
+	if err == nil || !strings.Contains(err.Error(), "no l2 client") {
+		t.Fatalf("expected missing l2 client error, got %v", err)
+	}
+}
+
+func Test_RegistryMissingAddress(t *testing.T) {
+	registry := NewRegistry(fakeAddresses{}, &fakeBackend{}, &fakeBackend{})
+
+	if _, err := registry.TaskAVSRegistrar(); err == nil {
+		t.Fatalf("expected an error for an unconfigured address")
+	}
+}
+
+func Test_RegistryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		l1      *fakeBackend
+		l2      *fakeBackend
+		wantErr string
+	}{
+		{
+			name: "deployed on the right chains",
+			l1:   &fakeBackend{deployed: map[common.Address]bool{l1Address: true}},
+			l2:   &fakeBackend{deployed: map[common.Address]bool{l2Address: true}},
+		},
+		{
+			name:    "L2 contract address points at L1",
+			l1:      &fakeBackend{deployed: map[common.Address]bool{l1Address: true, l2Address: true}},
+			l2:      &fakeBackend{deployed: map[common.Address]bool{}},
+			wantErr: "HelloWorldL2: no contract at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addresses := fakeAddresses{
+				HelloWorldL1.EnvName: l1Address,
+				HelloWorldL2.EnvName: l2Address,
+			}
+			err := NewRegistry(addresses, tt.l1, tt.l2).Validate(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_BindingRevertsAreRegistered(t *testing.T) {
+	parsed, err := TaskAVSRegistrar.MetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notAllowed := parsed.Errors["OperatorNotInAllowlist"]
+	revert := reverts.Default.DecodeData(notAllowed.ID[:4])
+	if revert.Reason() != "TaskAVSRegistrar.OperatorNotInAllowlist()" {
+		t.Errorf("Reason() = %q", revert.Reason())
+	}
+}
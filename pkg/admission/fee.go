@@ -0,0 +1,239 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrFeeTooLow is matched (via errors.Is) by every error returned when a task
+// is rejected because it does not pay the handler's minimum fee.
+var ErrFeeTooLow = errors.New("fee too low")
+
+// FeeError is returned when a task pays less than its handler's minimum fee,
+// or in a token other than the configured one. It carries the gRPC
+// FailedPrecondition code.
+type FeeError struct {
+	Handler string
+	Reason  string
+}
+
+func (e *FeeError) Error() string {
+	return fmt.Sprintf("%s: handler %q: %s", ErrFeeTooLow, e.Handler, e.Reason)
+}
+
+func (e *FeeError) Is(target error) bool {
+	return target == ErrFeeTooLow
+}
+
+// GRPCStatus allows the gRPC server to map this error to codes.FailedPrecondition.
+func (e *FeeError) GRPCStatus() *status.Status {
+	return status.New(codes.FailedPrecondition, e.Error())
+}
+
+// FeeConfig holds the minimum fee of each handler, in whole fee tokens.
+type FeeConfig struct {
+	// Default is the minimum fee of handlers without an entry in Handlers.
+	Default  *big.Rat
+	Handlers map[string]*big.Rat
+
+	// Token, if set, is the only fee token accepted.
+	Token common.Address
+}
+
+// FeeConfigFromEnv reads the fee configuration from the environment:
+//
+//	PERFORMER_MIN_FEE          minimum fee of every handler in whole tokens, e.g. "0.5"
+//	PERFORMER_HANDLER_MIN_FEES per-handler overrides, e.g. "default=0.5,heavy=10"
+//	PERFORMER_FEE_TOKEN        the only accepted fee token address
+func FeeConfigFromEnv() (FeeConfig, error) {
+	cfg := FeeConfig{Handlers: make(map[string]*big.Rat)}
+
+	if v := os.Getenv("PERFORMER_MIN_FEE"); v != "" {
+		fee, err := parseFee(v)
+		if err != nil {
+			return FeeConfig{}, fmt.Errorf("invalid PERFORMER_MIN_FEE: %w", err)
+		}
+		cfg.Default = fee
+	}
+	if v := os.Getenv("PERFORMER_HANDLER_MIN_FEES"); v != "" {
+		for _, entry := range strings.Split(v, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			name, spec, ok := strings.Cut(entry, "=")
+			if !ok || name == "" {
+				return FeeConfig{}, fmt.Errorf("invalid PERFORMER_HANDLER_MIN_FEES: expected name=fee, got %q", entry)
+			}
+			fee, err := parseFee(spec)
+			if err != nil {
+				return FeeConfig{}, fmt.Errorf("invalid PERFORMER_HANDLER_MIN_FEES: handler %q: %w", name, err)
+			}
+			cfg.Handlers[name] = fee
+		}
+	}
+	if v := os.Getenv("PERFORMER_FEE_TOKEN"); v != "" {
+		if !common.IsHexAddress(v) {
+			return FeeConfig{}, fmt.Errorf("invalid PERFORMER_FEE_TOKEN %q", v)
+		}
+		cfg.Token = common.HexToAddress(v)
+	}
+	return cfg, nil
+}
+
+func parseFee(s string) (*big.Rat, error) {
+	fee, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || fee.Sign() < 0 {
+		return nil, fmt.Errorf("%q is not a non-negative decimal", s)
+	}
+	return fee, nil
+}
+
+// Enabled reports whether any minimum fee or fee token is configured.
+func (c FeeConfig) Enabled() bool {
+	return c.Default != nil || len(c.Handlers) > 0 || c.Token != (common.Address{})
+}
+
+// Minimum returns the minimum fee of handler in whole tokens, or nil if it has
+// none.
+func (c FeeConfig) Minimum(handler string) *big.Rat {
+	if fee, ok := c.Handlers[handler]; ok {
+		return fee
+	}
+	return c.Default
+}
+
+// DecimalsReader reads the number of decimals of an ERC20 token.
+type DecimalsReader interface {
+	Decimals(ctx context.Context, token common.Address) (uint8, error)
+}
+
+// FeeChecker compares the fee a task pays with its handler's minimum.
+type FeeChecker struct {
+	cfg    FeeConfig
+	reader DecimalsReader
+
+	mu       sync.Mutex
+	decimals map[common.Address]uint8
+}
+
+// NewFeeChecker creates a FeeChecker. Token decimals are read once per token.
+func NewFeeChecker(cfg FeeConfig, reader DecimalsReader) *FeeChecker {
+	return &FeeChecker{
+		cfg:      cfg,
+		reader:   reader,
+		decimals: make(map[common.Address]uint8),
+	}
+}
+
+// Check returns a *FeeError if task pays less than the minimum fee of handler,
+// or pays in a token other than the configured one.
+func (f *FeeChecker) Check(ctx context.Context, handler string, task *mailbox.Task) error {
+	token := task.ExecutorOperatorSetTaskConfig.FeeToken
+	if f.cfg.Token != (common.Address{}) && token != f.cfg.Token {
+		return &FeeError{Handler: handler, Reason: fmt.Sprintf("task pays in token %s, only %s is accepted", token, f.cfg.Token)}
+	}
+
+	minimum := f.cfg.Minimum(handler)
+	if minimum == nil || minimum.Sign() == 0 {
+		return nil
+	}
+	if token == (common.Address{}) {
+		return &FeeError{Handler: handler, Reason: fmt.Sprintf("task has no fee token, minimum fee is %s", minimum.FloatString(6))}
+	}
+
+	decimals, err := f.tokenDecimals(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to read decimals of fee token %s: %w", token, err)
+	}
+
+	paid := task.AvsFee
+	if paid == nil {
+		paid = new(big.Int)
+	}
+	required := toBaseUnits(minimum, decimals)
+	if paid.Cmp(required) < 0 {
+		return &FeeError{Handler: handler, Reason: fmt.Sprintf("task pays %s, minimum is %s (token %s)",
+			FormatUnits(paid, decimals), FormatUnits(required, decimals), token)}
+	}
+	return nil
+}
+
+func (f *FeeChecker) tokenDecimals(ctx context.Context, token common.Address) (uint8, error) {
+	f.mu.Lock()
+	decimals, ok := f.decimals[token]
+	f.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+
+	decimals, err := f.reader.Decimals(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	f.mu.Lock()
+	f.decimals[token] = decimals
+	f.mu.Unlock()
+	return decimals, nil
+}
+
+// toBaseUnits converts an amount of whole tokens to base units, rounding up
+// so that the minimum is never undercut.
+func toBaseUnits(amount *big.Rat, decimals uint8) *big.Int {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	scaled := new(big.Rat).Mul(amount, new(big.Rat).SetInt(scale))
+	q, r := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if r.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// FormatUnits formats an amount in base units as whole tokens.
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	s := new(big.Rat).SetFrac(amount, scale).FloatString(int(decimals))
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+const erc20DecimalsABI = `[{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}]`
+
+// ERC20Decimals reads token decimals over a contract backend.
+type ERC20Decimals struct {
+	backend bind.ContractCaller
+	abi     abi.ABI
+}
+
+// NewERC20Decimals creates an ERC20Decimals that calls tokens over backend.
+func NewERC20Decimals(backend bind.ContractCaller) (*ERC20Decimals, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20DecimalsABI))
+	if err != nil {
+		return nil, err
+	}
+	return &ERC20Decimals{backend: backend, abi: parsed}, nil
+}
+
+// Decimals calls decimals() on token.
+func (e *ERC20Decimals) Decimals(ctx context.Context, token common.Address) (uint8, error) {
+	contract := bind.NewBoundContract(token, e.abi, e.backend, nil, nil)
+	var out []any
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "decimals"); err != nil {
+		return 0, err
+	}
+	return *abi.ConvertType(out[0], new(uint8)).(*uint8), nil
+}
@@ -0,0 +1,95 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var feeToken = common.HexToAddress("0xfee0000000000000000000000000000000000001")
+
+type fakeDecimals struct {
+	decimals uint8
+	calls    int
+}
+
+func (f *fakeDecimals) Decimals(ctx context.Context, token common.Address) (uint8, error) {
+	f.calls++
+	return f.decimals, nil
+}
+
+func feeTask(token common.Address, fee int64) *mailbox.Task {
+	task := &mailbox.Task{AvsFee: big.NewInt(fee), Status: uint8(mailbox.TaskStatusCreated)}
+	task.ExecutorOperatorSetTaskConfig.FeeToken = token
+	return task
+}
+
+func expectFeeTooLow(t *testing.T, err error) {
+	t.Helper()
+	var feeErr *FeeError
+	if !errors.As(err, &feeErr) || !errors.Is(err, ErrFeeTooLow) {
+		t.Fatalf("expected a FeeError, got %v", err)
+	}
+	if got := status.Code(err); got != codes.FailedPrecondition {
+		t.Errorf("gRPC code = %s, want %s", got, codes.FailedPrecondition)
+	}
+}
+
+func Test_FeeCheck(t *testing.T) {
+	t.Setenv("PERFORMER_MIN_FEE", "1.5")
+	t.Setenv("PERFORMER_HANDLER_MIN_FEES", "free=0")
+	t.Setenv("PERFORMER_FEE_TOKEN", feeToken.Hex())
+	cfg, err := FeeConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := &fakeDecimals{decimals: 6}
+	checker := NewFeeChecker(cfg, reader)
+	ctx := context.Background()
+
+	if err := checker.Check(ctx, "default", feeTask(feeToken, 1_500_000)); err != nil {
+		t.Fatalf("expected the minimum fee to be accepted, got %v", err)
+	}
+	err = checker.Check(ctx, "default", feeTask(feeToken, 1_499_999))
+	expectFeeTooLow(t, err)
+	if want := "fee too low: handler \"default\": task pays 1.499999, minimum is 1.5 (token " + feeToken.Hex() + ")"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	if reader.calls != 1 {
+		t.Errorf("decimals read %d times, want 1", reader.calls)
+	}
+
+	// Handlers with a zero minimum accept any fee, but only in the right token.
+	if err := checker.Check(ctx, "free", feeTask(feeToken, 0)); err != nil {
+		t.Fatalf("expected a free handler to accept the task, got %v", err)
+	}
+	expectFeeTooLow(t, checker.Check(ctx, "free", feeTask(common.HexToAddress("0x1"), 0)))
+}
+
+func Test_FeeCheckWithoutToken(t *testing.T) {
+	checker := NewFeeChecker(FeeConfig{Default: big.NewRat(1, 1)}, &fakeDecimals{decimals: 18})
+	expectFeeTooLow(t, checker.Check(context.Background(), "default", feeTask(common.Address{}, 1e18)))
+}
+
+func Test_ToBaseUnitsRoundsUp(t *testing.T) {
+	// 1/3 of a token with 2 decimals is 33.33 base units, so 34 are required.
+	if got := toBaseUnits(big.NewRat(1, 3), 2); got.Cmp(big.NewInt(34)) != 0 {
+		t.Errorf("toBaseUnits(1/3, 2) = %s, want 34", got)
+	}
+	if got := FormatUnits(big.NewInt(1_000_000_000_000_000_000), 18); got != "1" {
+		t.Errorf("FormatUnits = %q, want \"1\"", got)
+	}
+}
+
+func Test_FeeConfigFromEnvRejectsNegativeFees(t *testing.T) {
+	t.Setenv("PERFORMER_MIN_FEE", "-1")
+	if _, err := FeeConfigFromEnv(); err == nil {
+		t.Fatal("expected an error for a negative fee")
+	}
+}
@@ -0,0 +1,335 @@
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrPolicyRejected is matched (via errors.Is) by every error returned when a
+// task is rejected by the creator policy.
+var ErrPolicyRejected = errors.New("task rejected by creator policy")
+
+// Policy rules that can reject a task.
+const (
+	RuleAllowlist      = "allowlist"
+	RuleDenylist       = "denylist"
+	RuleTasksPerMinute = "tasksPerMinute"
+	RuleComputePerDay  = "computeSecondsPerDay"
+	RuleUnknownCreator = "unknownCreator"
+)
+
+// PolicyError is returned when the creator policy rejects a task. List rules
+// carry the gRPC PermissionDenied code and quota rules ResourceExhausted.
+type PolicyError struct {
+	Creator common.Address
+	Rule    string
+	Reason  string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s: creator %s: %s", ErrPolicyRejected, e.Creator, e.Reason)
+}
+
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrPolicyRejected
+}
+
+// GRPCStatus allows the gRPC server to map this error to a status code.
+func (e *PolicyError) GRPCStatus() *status.Status {
+	switch e.Rule {
+	case RuleTasksPerMinute, RuleComputePerDay:
+		return status.New(codes.ResourceExhausted, e.Error())
+	default:
+		return status.New(codes.PermissionDenied, e.Error())
+	}
+}
+
+// Quota limits the work done for one creator. Zero values are unlimited.
+type Quota struct {
+	TasksPerMinute       int     `json:"tasksPerMinute"`
+	ComputeSecondsPerDay float64 `json:"computeSecondsPerDay"`
+}
+
+// PolicyRules is the content of the policy file:
+//
+//	{
+//	  "allow": ["0x..."],
+//	  "deny": ["0x..."],
+//	  "defaultQuota": {"tasksPerMinute": 10, "computeSecondsPerDay": 600},
+//	  "quotas": {"0x...": {"tasksPerMinute": 100}}
+//	}
+//
+// If allow is not empty, only the listed creators are served. deny always
+// wins over allow. quotas override defaultQuota per creator.
+type PolicyRules struct {
+	Allow        []common.Address         `json:"allow"`
+	Deny         []common.Address         `json:"deny"`
+	DefaultQuota Quota                    `json:"defaultQuota"`
+	Quotas       map[common.Address]Quota `json:"quotas"`
+}
+
+// ParsePolicyRules parses a policy file.
+func ParsePolicyRules(data []byte) (*PolicyRules, error) {
+	var rules PolicyRules
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// LoadPolicyRules reads and parses the policy file at path.
+func LoadPolicyRules(path string) (*PolicyRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := ParsePolicyRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+func (r *PolicyRules) quota(creator common.Address) Quota {
+	if q, ok := r.Quotas[creator]; ok {
+		return q
+	}
+	return r.DefaultQuota
+}
+
+// Decision is the outcome of a policy check, for the audit log.
+type Decision struct {
+	Creator common.Address
+	Allowed bool
+	// Rule is the rule that rejected the task; empty if it was allowed.
+	Rule   string
+	Reason string
+}
+
+// Policy applies allow and deny lists and rolling per-creator quotas. Usage is
+// kept when the rules are replaced.
+type Policy struct {
+	mu    sync.Mutex
+	rules *PolicyRules
+	usage map[common.Address]*creatorUsage
+	// evicted is when creators without recent usage were last dropped.
+	evicted time.Time
+
+	// modTime is the modification time of the file the rules were loaded from.
+	modTime time.Time
+}
+
+type creatorUsage struct {
+	tasks   rolling
+	compute rolling
+}
+
+// evictInterval is how often Admit drops creators whose usage windows are
+// empty, so that tasks from many one-off creators don't grow the usage map
+// without bound.
+const evictInterval = time.Minute
+
+// NewPolicy creates a Policy with the given rules.
+func NewPolicy(rules *PolicyRules) *Policy {
+	return &Policy{
+		rules: rules,
+		usage: make(map[common.Address]*creatorUsage),
+	}
+}
+
+// NewPolicyFromFile creates a Policy with the rules in the file at path.
+func NewPolicyFromFile(path string) (*Policy, error) {
+	// Stat before reading, so that a change made while reading is picked up
+	// by WatchFile.
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := LoadPolicyRules(path)
+	if err != nil {
+		return nil, err
+	}
+	p := NewPolicy(rules)
+	p.modTime = info.ModTime()
+	return p, nil
+}
+
+// SetRules replaces the policy's rules.
+func (p *Policy) SetRules(rules *PolicyRules) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = rules
+}
+
+// Admit decides whether a task from creator may run. Admitted tasks count
+// towards the creator's tasks per minute. The returned error is a
+// *PolicyError when the task is rejected.
+func (p *Policy) Admit(creator common.Address, now time.Time) (Decision, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	reject := func(rule string, reason string) (Decision, error) {
+		return Decision{Creator: creator, Rule: rule, Reason: reason}, &PolicyError{Creator: creator, Rule: rule, Reason: reason}
+	}
+
+	for _, denied := range p.rules.Deny {
+		if denied == creator {
+			return reject(RuleDenylist, "creator is denied")
+		}
+	}
+	if len(p.rules.Allow) > 0 {
+		allowed := false
+		for _, a := range p.rules.Allow {
+			if a == creator {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return reject(RuleAllowlist, "creator is not allowed")
+		}
+	}
+
+	p.evictIdle(now)
+	quota := p.rules.quota(creator)
+	usage := p.usageOf(creator)
+	if quota.TasksPerMinute > 0 {
+		if n := usage.tasks.sum(now); n >= float64(quota.TasksPerMinute) {
+			return reject(RuleTasksPerMinute, fmt.Sprintf("%d tasks in the last minute, quota is %d", int(n), quota.TasksPerMinute))
+		}
+	}
+	if quota.ComputeSecondsPerDay > 0 {
+		if s := usage.compute.sum(now); s >= quota.ComputeSecondsPerDay {
+			return reject(RuleComputePerDay, fmt.Sprintf("%.1fs of compute in the last day, quota is %.1fs", s, quota.ComputeSecondsPerDay))
+		}
+	}
+
+	usage.tasks.add(now, 1)
+	return Decision{Creator: creator, Allowed: true, Reason: "within policy"}, nil
+}
+
+// RecordCompute adds the time spent handling a task to creator's usage.
+func (p *Policy) RecordCompute(creator common.Address, d time.Duration, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.usageOf(creator).compute.add(now, d.Seconds())
+}
+
+func (p *Policy) usageOf(creator common.Address) *creatorUsage {
+	usage, ok := p.usage[creator]
+	if !ok {
+		usage = &creatorUsage{
+			tasks:   rolling{window: time.Minute, bucket: time.Second},
+			compute: rolling{window: 24 * time.Hour, bucket: time.Minute},
+		}
+		p.usage[creator] = usage
+	}
+	return usage
+}
+
+// evictIdle drops the usage of creators with nothing left in either window,
+// at most once per evictInterval.
+func (p *Policy) evictIdle(now time.Time) {
+	if now.Sub(p.evicted) < evictInterval {
+		return
+	}
+	p.evicted = now
+	for creator, usage := range p.usage {
+		usage.tasks.prune(now)
+		usage.compute.prune(now)
+		if len(usage.tasks.entries) == 0 && len(usage.compute.entries) == 0 {
+			delete(p.usage, creator)
+		}
+	}
+}
+
+// WatchFile reloads the rules from path whenever its modification time
+// changes, checking every interval until ctx is done. Rules that fail to load
+// are reported to onError and the current rules are kept.
+func (p *Policy) WatchFile(ctx context.Context, path string, interval time.Duration, onReload func(), onError func(error)) {
+	p.mu.Lock()
+	modTime := p.modTime
+	p.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			onError(err)
+			continue
+		}
+		if info.ModTime().Equal(modTime) {
+			continue
+		}
+		modTime = info.ModTime()
+
+		rules, err := LoadPolicyRules(path)
+		if err != nil {
+			onError(err)
+			continue
+		}
+		p.SetRules(rules)
+		onReload()
+	}
+}
+
+// rolling sums values added within the last window, in buckets of the given
+// width.
+type rolling struct {
+	window  time.Duration
+	bucket  time.Duration
+	entries []rollingEntry
+}
+
+type rollingEntry struct {
+	start time.Time
+	value float64
+}
+
+func (r *rolling) add(now time.Time, v float64) {
+	r.prune(now)
+	start := now.Truncate(r.bucket)
+	if n := len(r.entries); n > 0 && r.entries[n-1].start.Equal(start) {
+		r.entries[n-1].value += v
+		return
+	}
+	r.entries = append(r.entries, rollingEntry{start: start, value: v})
+}
+
+func (r *rolling) sum(now time.Time) float64 {
+	r.prune(now)
+	var total float64
+	for _, e := range r.entries {
+		total += e.value
+	}
+	return total
+}
+
+// prune drops buckets that lie entirely outside the window.
+func (r *rolling) prune(now time.Time) {
+	cutoff := now.Add(-r.window)
+	i := 0
+	for i < len(r.entries) && !r.entries[i].start.Add(r.bucket).After(cutoff) {
+		i++
+	}
+	r.entries = r.entries[i:]
+}
@@ -0,0 +1,170 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	alice   = common.HexToAddress("0xa11ce00000000000000000000000000000000001")
+	bob     = common.HexToAddress("0xb0b0000000000000000000000000000000000002")
+	mallory = common.HexToAddress("0x3a11000000000000000000000000000000000003")
+)
+
+func mustParse(t *testing.T, s string) *PolicyRules {
+	t.Helper()
+	rules, err := ParsePolicyRules([]byte(s))
+	if err != nil {
+		t.Fatalf("ParsePolicyRules: %v", err)
+	}
+	return rules
+}
+
+func expectRule(t *testing.T, err error, rule string, code codes.Code) {
+	t.Helper()
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) || !errors.Is(err, ErrPolicyRejected) {
+		t.Fatalf("expected a PolicyError, got %v", err)
+	}
+	if policyErr.Rule != rule {
+		t.Errorf("Rule = %q, want %q", policyErr.Rule, rule)
+	}
+	if got := status.Code(err); got != code {
+		t.Errorf("gRPC code = %s, want %s", got, code)
+	}
+}
+
+func Test_PolicyLists(t *testing.T) {
+	policy := NewPolicy(mustParse(t, `{
+		"allow": ["`+alice.Hex()+`", "`+mallory.Hex()+`"],
+		"deny": ["`+mallory.Hex()+`"]
+	}`))
+	now := time.Now()
+
+	if d, err := policy.Admit(alice, now); err != nil || !d.Allowed {
+		t.Fatalf("expected alice to be allowed, got %+v, %v", d, err)
+	}
+	_, err := policy.Admit(bob, now)
+	expectRule(t, err, RuleAllowlist, codes.PermissionDenied)
+	_, err = policy.Admit(mallory, now)
+	expectRule(t, err, RuleDenylist, codes.PermissionDenied)
+}
+
+func Test_PolicyTasksPerMinute(t *testing.T) {
+	policy := NewPolicy(mustParse(t, `{
+		"defaultQuota": {"tasksPerMinute": 2},
+		"quotas": {"`+alice.Hex()+`": {"tasksPerMinute": 0}}
+	}`))
+	start := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := policy.Admit(bob, start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("task %d: %v", i, err)
+		}
+	}
+	_, err := policy.Admit(bob, start.Add(2*time.Second))
+	expectRule(t, err, RuleTasksPerMinute, codes.ResourceExhausted)
+
+	// The window rolls: a minute after the first task there is room again.
+	if _, err := policy.Admit(bob, start.Add(61*time.Second)); err != nil {
+		t.Fatalf("expected the quota to roll over, got %v", err)
+	}
+
+	// alice's override lifts the limit.
+	for i := 0; i < 10; i++ {
+		if _, err := policy.Admit(alice, start); err != nil {
+			t.Fatalf("alice task %d: %v", i, err)
+		}
+	}
+}
+
+func Test_PolicyComputePerDay(t *testing.T) {
+	policy := NewPolicy(mustParse(t, `{"defaultQuota": {"computeSecondsPerDay": 60}}`))
+	start := time.Unix(1_700_000_000, 0)
+
+	if _, err := policy.Admit(bob, start); err != nil {
+		t.Fatal(err)
+	}
+	policy.RecordCompute(bob, time.Minute, start)
+
+	_, err := policy.Admit(bob, start.Add(time.Hour))
+	expectRule(t, err, RuleComputePerDay, codes.ResourceExhausted)
+
+	if _, err := policy.Admit(bob, start.Add(25*time.Hour)); err != nil {
+		t.Fatalf("expected the daily quota to roll over, got %v", err)
+	}
+}
+
+func Test_PolicyEvictsIdleCreators(t *testing.T) {
+	policy := NewPolicy(mustParse(t, `{"defaultQuota": {"tasksPerMinute": 1}}`))
+	start := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 100; i++ {
+		creator := common.BigToAddress(big.NewInt(int64(i + 1)))
+		if _, err := policy.Admit(creator, start); err != nil {
+			t.Fatalf("creator %d: %v", i, err)
+		}
+	}
+	policy.RecordCompute(bob, time.Second, start)
+
+	// A minute later every task window is empty, but bob's compute still
+	// counts towards the daily quota
+	if _, err := policy.Admit(alice, start.Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := policy.usage[bob]; !ok || len(policy.usage) != 2 {
+		t.Errorf("expected only alice and bob to be tracked, got %d creators", len(policy.usage))
+	}
+}
+
+func Test_ParsePolicyRulesRejectsUnknownFields(t *testing.T) {
+	if _, err := ParsePolicyRules([]byte(`{"allowlist": []}`)); err == nil {
+		t.Fatalf("expected an error for a misspelled field")
+	}
+}
+
+func Test_PolicyWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := NewPolicyFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan struct{}, 1)
+	go policy.WatchFile(ctx, path, 10*time.Millisecond,
+		func() { reloaded <- struct{}{} },
+		func(err error) { t.Errorf("reload failed: %v", err) },
+	)
+
+	if err := os.WriteFile(path, []byte(`{"deny": ["`+bob.Hex()+`"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the modification time changes on filesystems with coarse
+	// timestamps.
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("policy was not reloaded")
+	}
+	_, err = policy.Admit(bob, time.Now())
+	expectRule(t, err, RuleDenylist, codes.PermissionDenied)
+}
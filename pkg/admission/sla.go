@@ -0,0 +1,154 @@
+// Package admission decides whether the Performer should take on a task before
+// any work is done for it.
+package admission
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrDeadline is matched (via errors.Is) by every error returned when a task
+// is rejected because it cannot finish before its deadline.
+var ErrDeadline = errors.New("task cannot finish before its deadline")
+
+// DeadlineError is returned when a task's remaining SLA is shorter than its
+// handler's expected duration. It carries the gRPC DeadlineExceeded code.
+type DeadlineError struct {
+	Handler   string
+	Deadline  time.Time
+	Remaining time.Duration
+	Expected  time.Duration
+}
+
+func (e *DeadlineError) Error() string {
+	return fmt.Sprintf("%s: handler %q needs %s but %s remain until %s",
+		ErrDeadline, e.Handler, e.Expected, e.Remaining.Round(time.Millisecond), e.Deadline.UTC().Format(time.RFC3339))
+}
+
+func (e *DeadlineError) Is(target error) bool {
+	return target == ErrDeadline
+}
+
+// GRPCStatus allows the gRPC server to map this error to codes.DeadlineExceeded.
+func (e *DeadlineError) GRPCStatus() *status.Status {
+	return status.New(codes.DeadlineExceeded, e.Error())
+}
+
+// SLAConfig holds the expected duration of each handler.
+type SLAConfig struct {
+	// Default is the expected duration of handlers without an entry in
+	// Handlers. Zero admits any task that has not yet expired.
+	Default  time.Duration
+	Handlers map[string]time.Duration
+
+	// Margin is reserved after the handler finishes, for the Executor to sign
+	// the result and the Aggregator to submit it before the deadline.
+	Margin time.Duration
+}
+
+// SLAConfigFromEnv reads the SLA configuration from the environment:
+//
+//	PERFORMER_EXPECTED_TASK_DURATION  expected duration of every handler, e.g. "5s"
+//	PERFORMER_HANDLER_DURATIONS       per-handler overrides, e.g. "default=5s,heavy=2m"
+//	PERFORMER_SLA_MARGIN              time reserved for signing and submission, e.g. "10s"
+func SLAConfigFromEnv() (SLAConfig, error) {
+	cfg := SLAConfig{Handlers: make(map[string]time.Duration)}
+
+	var err error
+	if cfg.Default, err = envDuration("PERFORMER_EXPECTED_TASK_DURATION"); err != nil {
+		return SLAConfig{}, err
+	}
+	if cfg.Margin, err = envDuration("PERFORMER_SLA_MARGIN"); err != nil {
+		return SLAConfig{}, err
+	}
+	if v := os.Getenv("PERFORMER_HANDLER_DURATIONS"); v != "" {
+		if cfg.Handlers, err = ParseHandlerDurations(v); err != nil {
+			return SLAConfig{}, fmt.Errorf("invalid PERFORMER_HANDLER_DURATIONS: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// ParseHandlerDurations parses a comma separated list of name=duration
+// entries.
+func ParseHandlerDurations(s string) (map[string]time.Duration, error) {
+	out := make(map[string]time.Duration)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=duration, got %q", entry)
+		}
+		d, err := time.ParseDuration(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for handler %q: %w", name, err)
+		}
+		out[name] = d
+	}
+	return out, nil
+}
+
+// Enabled reports whether any expected duration or margin is configured.
+func (c SLAConfig) Enabled() bool {
+	return c.Default > 0 || c.Margin > 0 || len(c.Handlers) > 0
+}
+
+// Expected returns the expected duration of handler.
+func (c SLAConfig) Expected(handler string) time.Duration {
+	if d, ok := c.Handlers[handler]; ok {
+		return d
+	}
+	return c.Default
+}
+
+// Admit checks that handler can finish before deadline, leaving the margin
+// for signing and submission. It returns the time the handler may use.
+func (c SLAConfig) Admit(handler string, deadline time.Time, now time.Time) (time.Duration, error) {
+	remaining := deadline.Sub(now) - c.Margin
+	expected := c.Expected(handler)
+	if remaining <= 0 || remaining < expected {
+		return 0, &DeadlineError{
+			Handler:   handler,
+			Deadline:  deadline,
+			Remaining: max(remaining, 0),
+			Expected:  expected,
+		}
+	}
+	return remaining, nil
+}
+
+// TaskDeadline returns the deadline of a task read from the TaskMailbox: its
+// creation time plus the SLA of its executor operator set. ok is false if the
+// task is unknown or has no SLA.
+func TaskDeadline(task *mailbox.Task) (deadline time.Time, ok bool) {
+	if task == nil || task.TaskStatus() == mailbox.TaskStatusNone || task.CreationTime == nil {
+		return time.Time{}, false
+	}
+	sla := task.ExecutorOperatorSetTaskConfig.TaskSLA
+	if sla == nil || sla.Sign() <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(task.CreationTime.Int64()+sla.Int64(), 0), true
+}
+
+func envDuration(name string) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return d, nil
+}
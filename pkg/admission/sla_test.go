@@ -0,0 +1,98 @@
+package admission
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Admit(t *testing.T) {
+	cfg := SLAConfig{
+		Default:  5 * time.Second,
+		Handlers: map[string]time.Duration{"heavy": time.Minute},
+		Margin:   10 * time.Second,
+	}
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name     string
+		handler  string
+		deadline time.Time
+		want     time.Duration
+		wantErr  bool
+	}{
+		{"enough time", "default", now.Add(time.Minute), 50 * time.Second, false},
+		{"exactly enough", "default", now.Add(15 * time.Second), 5 * time.Second, false},
+		{"eaten by the margin", "default", now.Add(12 * time.Second), 0, true},
+		{"handler override", "heavy", now.Add(time.Minute), 0, true},
+		{"already expired", "default", now.Add(-time.Second), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.Admit(tt.handler, tt.deadline, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrDeadline) {
+					t.Fatalf("expected ErrDeadline, got %v", err)
+				}
+				if code := status.Code(err); code != codes.DeadlineExceeded {
+					t.Errorf("gRPC code = %s, want DeadlineExceeded", code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Admit: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Admit() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_TaskDeadline(t *testing.T) {
+	task := &mailbox.Task{
+		CreationTime: big.NewInt(1_700_000_000),
+		Status:       uint8(mailbox.TaskStatusCreated),
+		ExecutorOperatorSetTaskConfig: mailbox.ExecutorOperatorSetTaskConfig{
+			TaskSLA: big.NewInt(120),
+		},
+	}
+	deadline, ok := TaskDeadline(task)
+	if !ok || !deadline.Equal(time.Unix(1_700_000_120, 0)) {
+		t.Errorf("TaskDeadline() = %s, %v", deadline, ok)
+	}
+
+	if _, ok := TaskDeadline(&mailbox.Task{}); ok {
+		t.Errorf("expected no deadline for an unknown task")
+	}
+	if _, ok := TaskDeadline(nil); ok {
+		t.Errorf("expected no deadline without a task")
+	}
+}
+
+func Test_SLAConfigFromEnv(t *testing.T) {
+	t.Setenv("PERFORMER_EXPECTED_TASK_DURATION", "5s")
+	t.Setenv("PERFORMER_HANDLER_DURATIONS", "heavy=2m, light=100ms")
+	t.Setenv("PERFORMER_SLA_MARGIN", "")
+
+	cfg, err := SLAConfigFromEnv()
+	if err != nil {
+		t.Fatalf("SLAConfigFromEnv: %v", err)
+	}
+	if !cfg.Enabled() {
+		t.Errorf("expected SLA admission to be enabled")
+	}
+	if cfg.Expected("default") != 5*time.Second || cfg.Expected("heavy") != 2*time.Minute || cfg.Expected("light") != 100*time.Millisecond {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	t.Setenv("PERFORMER_HANDLER_DURATIONS", "heavy")
+	if _, err := SLAConfigFromEnv(); err == nil {
+		t.Errorf("expected an error for a malformed entry")
+	}
+}
@@ -0,0 +1,339 @@
+// Package bindgen generates the Go contract bindings in contracts/bindings
+// from forge build artifacts, along with a registry that maps each contract's
+// executor environment variable name (e.g. HELLO_WORLD_L1) to its binding.
+package bindgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/deployments"
+	"github.com/ethereum/go-ethereum/accounts/abi/abigen"
+)
+
+// Chains are the source directories bindings are generated for, keyed by the
+// bindings subdirectory.
+var Chains = map[string]string{
+	"l1": "l1-contracts",
+	"l2": "l2-contracts",
+}
+
+// RegistryFile is the generated registry, relative to the bindings directory.
+const RegistryFile = "registry.go"
+
+// Config locates the sources, artifacts and output of a project.
+type Config struct {
+	// Root is the project root containing go.mod.
+	Root string
+
+	// SourcesDir holds l1-contracts and l2-contracts. Default: contracts/src.
+	SourcesDir string
+
+	// ArtifactsDir is forge's output directory. Default: .devkit/contracts/out.
+	ArtifactsDir string
+
+	// OutDir is the bindings directory. Default: contracts/bindings.
+	OutDir string
+}
+
+func (c *Config) withDefaults() Config {
+	cfg := *c
+	if cfg.Root == "" {
+		cfg.Root = "."
+	}
+	if cfg.SourcesDir == "" {
+		cfg.SourcesDir = filepath.Join(cfg.Root, "contracts", "src")
+	}
+	if cfg.ArtifactsDir == "" {
+		cfg.ArtifactsDir = filepath.Join(cfg.Root, ".devkit", "contracts", "out")
+	}
+	if cfg.OutDir == "" {
+		cfg.OutDir = filepath.Join(cfg.Root, "contracts", "bindings")
+	}
+	return cfg
+}
+
+// Contract is a Solidity contract to generate a binding for.
+type Contract struct {
+	Name    string // Solidity contract name, e.g. HelloWorldL1
+	Chain   string // l1 or l2
+	Package string // Go package name, e.g. helloworldl1
+	EnvName string // executor environment variable name, e.g. HELLO_WORLD_L1
+}
+
+// Dir is the binding's directory relative to the bindings directory.
+func (c Contract) Dir() string {
+	return c.Chain + "/" + c.Package
+}
+
+// File is the binding's file relative to the bindings directory.
+func (c Contract) File() string {
+	return c.Dir() + "/" + c.Package + ".go"
+}
+
+// EnvName converts a contract name to the environment variable name the
+// executor template uses for its address, e.g. TaskAVSRegistrar becomes
+// TASK_AVS_REGISTRAR.
+func EnvName(name string) string {
+	return deployments.EnvName(name)
+}
+
+// DiscoverContracts lists one contract per .sol file in each chain's source
+// directory, named after the file.
+func DiscoverContracts(sourcesDir string) ([]Contract, error) {
+	var contracts []Contract
+	for chain, dir := range Chains {
+		files, err := filepath.Glob(filepath.Join(sourcesDir, dir, "*.sol"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			name := strings.TrimSuffix(filepath.Base(f), ".sol")
+			contracts = append(contracts, Contract{
+				Name:    name,
+				Chain:   chain,
+				Package: strings.ToLower(name),
+				EnvName: EnvName(name),
+			})
+		}
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].File() < contracts[j].File()
+	})
+	return contracts, nil
+}
+
+// artifact is the part of a forge artifact bindings are generated from.
+type artifact struct {
+	ABI      json.RawMessage `json:"abi"`
+	Bytecode struct {
+		Object string `json:"object"`
+	} `json:"bytecode"`
+}
+
+func readArtifact(artifactsDir string, name string) (*artifact, error) {
+	path := filepath.Join(artifactsDir, name+".sol", name+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("contract artifact not found: %w (run 'devkit avs build' first)", err)
+	}
+	var a artifact
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("invalid artifact %s: %w", path, err)
+	}
+	return &a, nil
+}
+
+// Generate returns the contents of every generated file, keyed by path
+// relative to the bindings directory.
+func Generate(c *Config) (map[string][]byte, error) {
+	cfg := c.withDefaults()
+
+	module, err := modulePath(cfg.Root)
+	if err != nil {
+		return nil, err
+	}
+	bindingsImport, err := importPath(cfg.Root, module, cfg.OutDir)
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := DiscoverContracts(cfg.SourcesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, contract := range contracts {
+		a, err := readArtifact(cfg.ArtifactsDir, contract.Name)
+		if err != nil {
+			return nil, err
+		}
+		code, err := abigen.Bind(
+			[]string{contract.Name},
+			[]string{string(a.ABI)},
+			[]string{a.Bytecode.Object},
+			nil, contract.Package, nil, nil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate binding for %s: %w", contract.Name, err)
+		}
+		files[contract.File()] = []byte(code)
+	}
+
+	registry, err := generateRegistry(bindingsImport, contracts)
+	if err != nil {
+		return nil, err
+	}
+	files[RegistryFile] = registry
+	return files, nil
+}
+
+// Write replaces the generated files in the bindings directory, removing
+// bindings for contracts that no longer exist.
+func Write(c *Config, files map[string][]byte) error {
+	cfg := c.withDefaults()
+
+	stale, err := existingBindings(cfg.OutDir)
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		if _, ok := files[path]; !ok {
+			if err := os.RemoveAll(filepath.Join(cfg.OutDir, filepath.Dir(path))); err != nil {
+				return err
+			}
+		}
+	}
+
+	for path, data := range files {
+		full := filepath.Join(cfg.OutDir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(full, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check compares the generated files with the bindings directory and returns
+// a description of every difference. An empty result means the bindings are
+// up to date.
+func Check(c *Config, files map[string][]byte) ([]string, error) {
+	cfg := c.withDefaults()
+
+	var drift []string
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		existing, err := os.ReadFile(filepath.Join(cfg.OutDir, path))
+		switch {
+		case os.IsNotExist(err):
+			drift = append(drift, path+": missing")
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(existing, files[path]):
+			drift = append(drift, path+": out of date")
+		}
+	}
+
+	existing, err := existingBindings(cfg.OutDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range existing {
+		if _, ok := files[path]; !ok {
+			drift = append(drift, path+": no longer has a contract")
+		}
+	}
+	return drift, nil
+}
+
+// existingBindings lists generated binding files under the chain directories.
+func existingBindings(outDir string) ([]string, error) {
+	var paths []string
+	for chain := range Chains {
+		matches, err := filepath.Glob(filepath.Join(outDir, chain, "*", "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			rel, err := filepath.Rel(outDir, m)
+			if err != nil {
+				return nil, err
+			}
+			// Only files named after their package are generated
+			if filepath.Base(filepath.Dir(m))+".go" == filepath.Base(m) {
+				paths = append(paths, filepath.ToSlash(rel))
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func modulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module path in %s", filepath.Join(root, "go.mod"))
+}
+
+func importPath(root string, module string, dir string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absRoot, absDir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("bindings directory %s is outside the module root %s", dir, root)
+	}
+	return module + "/" + filepath.ToSlash(rel), nil
+}
+
+var registryTemplate = template.Must(template.New("registry").Parse(`// Code generated by bindgen. DO NOT EDIT.
+
+package bindings
+
+import ({{range .Contracts}}
+	"{{$.Import}}/{{.Dir}}"{{end}}
+)
+{{range .Contracts}}
+// {{.Name}} is the registry entry for {{.Package}}.{{.Name}}, deployed at ${{.EnvName}}.
+var {{.Name}} = Entry[*{{.Package}}.{{.Name}}]{
+	Name:     "{{.Name}}",
+	EnvName:  "{{.EnvName}}",
+	Chain:    {{if eq .Chain "l1"}}L1{{else}}L2{{end}},
+	MetaData: {{.Package}}.{{.Name}}MetaData,
+	New:      {{.Package}}.New{{.Name}},
+}
+
+// {{.Name}} returns the {{.Name}} binding on {{if eq .Chain "l1"}}L1{{else}}L2{{end}}.
+func (r *Registry) {{.Name}}() (*{{.Package}}.{{.Name}}, error) {
+	return Get(r, {{.Name}})
+}
+{{end}}
+// Contracts maps each contract's environment variable name to its entry.
+var Contracts = map[string]Contract{ {{- range .Contracts}}
+	"{{.EnvName}}": {{.Name}}.Contract(),{{end}}
+}
+`))
+
+func generateRegistry(bindingsImport string, contracts []Contract) ([]byte, error) {
+	var buf bytes.Buffer
+	err := registryTemplate.Execute(&buf, map[string]any{
+		"Import":    bindingsImport,
+		"Contracts": contracts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated registry does not compile: %w", err)
+	}
+	return code, nil
+}
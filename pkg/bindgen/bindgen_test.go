@@ -0,0 +1,138 @@
+package bindgen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings"
+)
+
+func Test_EnvName(t *testing.T) {
+	for name, want := range map[string]string{
+		"HelloWorldL1":     "HELLO_WORLD_L1",
+		"HelloWorldL2":     "HELLO_WORLD_L2",
+		"TaskAVSRegistrar": "TASK_AVS_REGISTRAR",
+		"AVSTaskHook":      "AVS_TASK_HOOK",
+		"taskMailbox":      "TASK_MAILBOX",
+	} {
+		if got := EnvName(name); got != want {
+			t.Errorf("EnvName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// internalTypeKind restores the space abigen strips from internal types such
+// as "struct OperatorSet"; struct names are derived from it.
+var internalTypeKind = regexp.MustCompile(`"internalType":"(struct|contract|enum)`)
+
+// writeArtifacts recreates forge artifacts from the checked-in bindings.
+func writeArtifacts(t *testing.T, dir string) {
+	t.Helper()
+	for _, c := range bindings.Contracts {
+		abi := internalTypeKind.ReplaceAllString(c.MetaData.ABI, `"internalType":"$1 `)
+		data, err := json.Marshal(map[string]any{
+			"abi":      json.RawMessage(abi),
+			"bytecode": map[string]string{"object": c.MetaData.Bin},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, c.Name+".sol", c.Name+".json")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_GenerateMatchesCheckedInBindings(t *testing.T) {
+	artifacts := t.TempDir()
+	writeArtifacts(t, artifacts)
+
+	cfg := &Config{Root: "../..", ArtifactsDir: artifacts}
+	files, err := Generate(cfg)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	drift, err := Check(cfg, files)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	for _, d := range drift {
+		t.Errorf("contracts/bindings/%s", d)
+	}
+}
+
+func Test_CheckDetectsDrift(t *testing.T) {
+	artifacts := t.TempDir()
+	writeArtifacts(t, artifacts)
+
+	// Copy the project layout the generator reads into a scratch root
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/avs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range bindings.Contracts {
+		dir := filepath.Join(root, "contracts", "src", Chains[string(c.Chain)])
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, c.Name+".sol"), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &Config{Root: root, ArtifactsDir: artifacts}
+	files, err := Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drift, _ := Check(cfg, files); len(drift) != len(files) {
+		t.Fatalf("expected every file to be missing before Write, got %v", drift)
+	}
+
+	if err := Write(cfg, files); err != nil {
+		t.Fatal(err)
+	}
+	if drift, _ := Check(cfg, files); len(drift) != 0 {
+		t.Fatalf("expected no drift after Write, got %v", drift)
+	}
+
+	// Edit a binding by hand
+	path := filepath.Join(root, "contracts", "bindings", "l1", "helloworldl1", "helloworldl1.go")
+	if err := os.WriteFile(path, []byte("package helloworldl1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Remove a contract's source
+	if err := os.Remove(filepath.Join(root, "contracts", "src", "l2-contracts", "HelloWorldL2.sol")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err = Generate(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drift, err := Check(cfg, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"l1/helloworldl1/helloworldl1.go: out of date",
+		"registry.go: out of date",
+		"l2/helloworldl2/helloworldl2.go: no longer has a contract",
+	}
+	if len(drift) != len(want) {
+		t.Fatalf("expected drift %v, got %v", want, drift)
+	}
+	for i := range want {
+		if drift[i] != want[i] {
+			t.Fatalf("expected drift %v, got %v", want, drift)
+		}
+	}
+}
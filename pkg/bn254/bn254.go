@@ -0,0 +1,152 @@
+// Package bn254 implements the BN254 operations EigenLayer uses for operator
+// keys, matching the on-chain BN254 library: points in the Solidity G1Point
+// and G2Point layout, hash-to-curve by try-and-increment and signatures in G1.
+package bn254
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+var (
+	// FieldModulus is the BN254 base field modulus (FP_MODULUS on-chain).
+	FieldModulus, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+
+	// Order is the order of G1 and G2 (FR_MODULUS on-chain).
+	Order, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+	// sqrtExponent is (p+1)/4, used to take square roots in the base field.
+	sqrtExponent = new(big.Int).Rsh(new(big.Int).Add(FieldModulus, big.NewInt(1)), 2)
+)
+
+// G1Point is a point on G1 in the layout of BN254.G1Point.
+type G1Point struct {
+	X *big.Int
+	Y *big.Int
+}
+
+// G2Point is a point on G2 in the layout of BN254.G2Point. Coordinates are
+// encoded as X[0]*i + X[1].
+type G2Point struct {
+	X [2]*big.Int
+	Y [2]*big.Int
+}
+
+// Marshal returns the 64 byte big-endian X || Y encoding.
+func (p G1Point) Marshal() []byte {
+	out := make([]byte, 64)
+	p.X.FillBytes(out[:32])
+	p.Y.FillBytes(out[32:])
+	return out
+}
+
+// Marshal returns the 128 byte big-endian X[0] || X[1] || Y[0] || Y[1] encoding.
+func (p G2Point) Marshal() []byte {
+	out := make([]byte, 128)
+	p.X[0].FillBytes(out[:32])
+	p.X[1].FillBytes(out[32:64])
+	p.Y[0].FillBytes(out[64:96])
+	p.Y[1].FillBytes(out[96:])
+	return out
+}
+
+// G1 converts p for use with the pairing library, checking it is on the curve.
+func (p G1Point) G1() (*bn256.G1, error) {
+	g := new(bn256.G1)
+	if _, err := g.Unmarshal(p.Marshal()); err != nil {
+		return nil, fmt.Errorf("invalid G1 point: %w", err)
+	}
+	return g, nil
+}
+
+// G2 converts p for use with the pairing library, checking it is on the curve.
+func (p G2Point) G2() (*bn256.G2, error) {
+	g := new(bn256.G2)
+	if _, err := g.Unmarshal(p.Marshal()); err != nil {
+		return nil, fmt.Errorf("invalid G2 point: %w", err)
+	}
+	return g, nil
+}
+
+// NewG1Point converts a point from the pairing library.
+func NewG1Point(g *bn256.G1) G1Point {
+	b := g.Marshal()
+	return G1Point{X: new(big.Int).SetBytes(b[:32]), Y: new(big.Int).SetBytes(b[32:])}
+}
+
+// NewG2Point converts a point from the pairing library.
+func NewG2Point(g *bn256.G2) G2Point {
+	b := g.Marshal()
+	return G2Point{
+		X: [2]*big.Int{new(big.Int).SetBytes(b[:32]), new(big.Int).SetBytes(b[32:64])},
+		Y: [2]*big.Int{new(big.Int).SetBytes(b[64:96]), new(big.Int).SetBytes(b[96:])},
+	}
+}
+
+// HashToG1 maps a message hash to G1 exactly as BN254.hashToG1 does on-chain.
+func HashToG1(hash [32]byte) G1Point {
+	x := new(big.Int).Mod(new(big.Int).SetBytes(hash[:]), FieldModulus)
+	for {
+		// beta = x^3 + 3, y = beta^((p+1)/4)
+		beta := new(big.Int).Exp(x, big.NewInt(3), FieldModulus)
+		beta.Add(beta, big.NewInt(3)).Mod(beta, FieldModulus)
+		y := new(big.Int).Exp(beta, sqrtExponent, FieldModulus)
+
+		if new(big.Int).Exp(y, big.NewInt(2), FieldModulus).Cmp(beta) == 0 {
+			return G1Point{X: x, Y: y}
+		}
+		x = new(big.Int).Add(x, big.NewInt(1))
+		x.Mod(x, FieldModulus)
+	}
+}
+
+// PrivateKey is a BN254 secret key.
+type PrivateKey struct {
+	secret *big.Int
+}
+
+// NewPrivateKey parses a secret key given as a decimal or 0x-prefixed hex
+// integer.
+func NewPrivateKey(s string) (*PrivateKey, error) {
+	s = strings.TrimSpace(s)
+	secret, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		// Accept bare hex as well
+		secret, ok = new(big.Int).SetString(s, 16)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid BN254 private key")
+	}
+	if secret.Sign() <= 0 || secret.Cmp(Order) >= 0 {
+		return nil, fmt.Errorf("BN254 private key out of range")
+	}
+	return &PrivateKey{secret: secret}, nil
+}
+
+// PublicKeyG1 returns the key's public key in G1.
+func (k *PrivateKey) PublicKeyG1() G1Point {
+	return NewG1Point(new(bn256.G1).ScalarBaseMult(k.secret))
+}
+
+// PublicKeyG2 returns the key's public key in G2.
+func (k *PrivateKey) PublicKeyG2() G2Point {
+	return NewG2Point(new(bn256.G2).ScalarBaseMult(k.secret))
+}
+
+// SignHash signs a message hash, returning HashToG1(hash) * secret.
+func (k *PrivateKey) SignHash(hash [32]byte) (G1Point, error) {
+	h, err := HashToG1(hash).G1()
+	if err != nil {
+		return G1Point{}, err
+	}
+	return NewG1Point(new(bn256.G1).ScalarMult(h, k.secret)), nil
+}
+
+// KeyData returns the public key encoding used by the KeyRegistrar, equal to
+// KeyRegistrar.encodeBN254KeyData(g1, g2).
+func KeyData(g1 G1Point, g2 G2Point) []byte {
+	return append(g1.Marshal(), g2.Marshal()...)
+}
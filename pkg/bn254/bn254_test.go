@@ -0,0 +1,60 @@
+package bn254
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+func Test_HashToG1(t *testing.T) {
+	for _, msg := range []string{"", "hello", "task"} {
+		p := HashToG1(crypto.Keccak256Hash([]byte(msg)))
+
+		// y^2 == x^3 + 3
+		lhs := new(big.Int).Exp(p.Y, big.NewInt(2), FieldModulus)
+		rhs := new(big.Int).Exp(p.X, big.NewInt(3), FieldModulus)
+		rhs.Add(rhs, big.NewInt(3)).Mod(rhs, FieldModulus)
+		if lhs.Cmp(rhs) != 0 {
+			t.Fatalf("HashToG1(%q) is not on the curve", msg)
+		}
+		if _, err := p.G1(); err != nil {
+			t.Fatalf("HashToG1(%q): %v", msg, err)
+		}
+	}
+}
+
+func Test_SignHash(t *testing.T) {
+	key, err := NewPrivateKey("0x1234567890abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := crypto.Keccak256Hash([]byte("register"))
+
+	sig, err := key.SignHash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// e(sig, g2) * e(-H(m), pk2) == 1
+	sigG1, _ := sig.G1()
+	h, _ := HashToG1(hash).G1()
+	h.Neg(h)
+	pk2, err := key.PublicKeyG2().G2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	if !bn256.PairingCheck([]*bn256.G1{sigG1, h}, []*bn256.G2{g2, pk2}) {
+		t.Fatal("signature does not verify")
+	}
+
+	if data := KeyData(key.PublicKeyG1(), key.PublicKeyG2()); len(data) != 192 {
+		t.Fatalf("expected 192 bytes of key data, got %d", len(data))
+	}
+
+	if _, err := NewPrivateKey("0"); err == nil {
+		t.Fatal("expected an error for a zero key")
+	}
+}
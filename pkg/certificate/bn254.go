@@ -0,0 +1,124 @@
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/bn254"
+	"github.com/ethereum/go-ethereum/crypto"
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// VerifyBN254 checks cert against the operator table operators at the
+// certificate's reference timestamp. digest is the message the operators
+// signed; pass cert.MessageHash unless the verifier contract signs a derived
+// digest (BN254CertificateVerifier.calculateCertificateDigest).
+//
+// Non-signers are taken from the certificate's witnesses, which must match
+// the operator table. The aggregate signature is checked against the
+// aggregate of the remaining operators' G1 keys and the certificate's G2
+// aggregate key, as BN254CertificateVerifier does on-chain.
+func VerifyBN254(cert *BN254Certificate, digest [32]byte, operators []BN254OperatorInfo) (*Result, error) {
+	result := &Result{}
+
+	nonSigner := make(map[int]bool, len(cert.NonSignerWitnesses))
+	for _, w := range cert.NonSignerWitnesses {
+		i := int(w.OperatorIndex)
+		if i >= len(operators) {
+			return nil, fmt.Errorf("non-signer index %d out of range for %d operators", i, len(operators))
+		}
+		if nonSigner[i] {
+			return nil, fmt.Errorf("non-signer index %d appears twice", i)
+		}
+		if !sameOperator(w.OperatorInfo, operators[i]) {
+			return nil, fmt.Errorf("non-signer witness %d does not match the operator table", i)
+		}
+		nonSigner[i] = true
+	}
+
+	// Aggregate the signers' keys and weights
+	var signerApk *bn256.G1
+	for i, op := range operators {
+		result.TotalWeights = sumWeights(result.TotalWeights, op.Weights)
+		if nonSigner[i] {
+			result.NonSigners = append(result.NonSigners, i)
+			continue
+		}
+		result.Signers = append(result.Signers, i)
+		result.SignedWeights = sumWeights(result.SignedWeights, op.Weights)
+
+		pk, err := op.Pubkey.G1()
+		if err != nil {
+			return nil, fmt.Errorf("operator %d: %w", i, err)
+		}
+		if signerApk == nil {
+			signerApk = pk
+		} else {
+			signerApk = new(bn256.G1).Add(signerApk, pk)
+		}
+	}
+	result.SignedWeights = padWeights(result.SignedWeights, len(result.TotalWeights))
+	if signerApk == nil {
+		// Nobody signed
+		return result, nil
+	}
+
+	valid, err := verifyAggregate(digest, bn254.NewG1Point(signerApk), cert.Apk, cert.Signature)
+	if err != nil {
+		return nil, err
+	}
+	result.Valid = valid
+	return result, nil
+}
+
+// verifyAggregate checks sigma against the G1 and G2 aggregate keys using the
+// randomised pairing check of BN254.safePairing:
+//
+//	e(sigma + apk*gamma, -g2) * e(H(m) + g1*gamma, apkG2) == 1
+func verifyAggregate(digest [32]byte, apk bn254.G1Point, apkG2 bn254.G2Point, sigma bn254.G1Point) (bool, error) {
+	gamma := new(big.Int).SetBytes(crypto.Keccak256(
+		digest[:],
+		apk.Marshal(),
+		apkG2.Marshal(),
+		sigma.Marshal(),
+	))
+	gamma.Mod(gamma, bn254.Order)
+
+	apkG1, err := apk.G1()
+	if err != nil {
+		return false, err
+	}
+	sigmaG1, err := sigma.G1()
+	if err != nil {
+		return false, fmt.Errorf("signature: %w", err)
+	}
+	apkG2Point, err := apkG2.G2()
+	if err != nil {
+		return false, fmt.Errorf("aggregate G2 key: %w", err)
+	}
+	h, err := bn254.HashToG1(digest).G1()
+	if err != nil {
+		return false, err
+	}
+
+	// Negate the G1 side rather than the generator in G2
+	lhs := new(bn256.G1).Add(sigmaG1, new(bn256.G1).ScalarMult(apkG1, gamma))
+	lhs.Neg(lhs)
+	rhs := new(bn256.G1).Add(h, new(bn256.G1).ScalarBaseMult(gamma))
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+
+	return bn256.PairingCheck([]*bn256.G1{lhs, rhs}, []*bn256.G2{g2, apkG2Point}), nil
+}
+
+func sameOperator(a BN254OperatorInfo, b BN254OperatorInfo) bool {
+	if !bytes.Equal(a.Pubkey.Marshal(), b.Pubkey.Marshal()) || len(a.Weights) != len(b.Weights) {
+		return false
+	}
+	for i := range a.Weights {
+		if a.Weights[i].Cmp(b.Weights[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+// Package certificate decodes and verifies the executor certificates the
+// Aggregator submits to the TaskMailbox, as found in the executorCert field of
+// TaskVerified events and getTaskInfo.
+package certificate
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/bn254"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// certificateABI is the getBN254CertificateBytes and getECDSACertificateBytes
+// fragment of the TaskMailbox ABI. Both return abi.encode(cert).
+const certificateABI = `[{"inputs": [{"components": [{"internalType": "uint32", "name": "referenceTimestamp", "type": "uint32"}, {"internalType": "bytes32", "name": "messageHash", "type": "bytes32"}, {"components": [{"internalType": "uint256", "name": "X", "type": "uint256"}, {"internalType": "uint256", "name": "Y", "type": "uint256"}], "internalType": "struct BN254.G1Point", "name": "signature", "type": "tuple"}, {"components": [{"internalType": "uint256[2]", "name": "X", "type": "uint256[2]"}, {"internalType": "uint256[2]", "name": "Y", "type": "uint256[2]"}], "internalType": "struct BN254.G2Point", "name": "apk", "type": "tuple"}, {"components": [{"internalType": "uint32", "name": "operatorIndex", "type": "uint32"}, {"internalType": "bytes", "name": "operatorInfoProof", "type": "bytes"}, {"components": [{"components": [{"internalType": "uint256", "name": "X", "type": "uint256"}, {"internalType": "uint256", "name": "Y", "type": "uint256"}], "internalType": "struct BN254.G1Point", "name": "pubkey", "type": "tuple"}, {"internalType": "uint256[]", "name": "weights", "type": "uint256[]"}], "internalType": "struct IOperatorTableCalculatorTypes.BN254OperatorInfo", "name": "operatorInfo", "type": "tuple"}], "internalType": "struct IBN254CertificateVerifierTypes.BN254OperatorInfoWitness[]", "name": "nonSignerWitnesses", "type": "tuple[]"}], "internalType": "struct IBN254CertificateVerifierTypes.BN254Certificate", "name": "cert", "type": "tuple"}], "name": "getBN254CertificateBytes", "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}], "stateMutability": "pure", "type": "function"}, {"inputs": [{"components": [{"internalType": "uint32", "name": "referenceTimestamp", "type": "uint32"}, {"internalType": "bytes32", "name": "messageHash", "type": "bytes32"}, {"internalType": "bytes", "name": "sig", "type": "bytes"}], "internalType": "struct IECDSACertificateVerifierTypes.ECDSACertificate", "name": "cert", "type": "tuple"}], "name": "getECDSACertificateBytes", "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}], "stateMutability": "pure", "type": "function"}]`
+
+var (
+	bn254CertArgs abi.Arguments
+	ecdsaCertArgs abi.Arguments
+)
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(certificateABI))
+	if err != nil {
+		panic(fmt.Errorf("invalid certificate ABI: %w", err))
+	}
+	bn254CertArgs = parsed.Methods["getBN254CertificateBytes"].Inputs
+	ecdsaCertArgs = parsed.Methods["getECDSACertificateBytes"].Inputs
+}
+
+// BN254OperatorInfo is an operator's entry in a BN254 operator table.
+type BN254OperatorInfo struct {
+	Pubkey  bn254.G1Point
+	Weights []*big.Int
+}
+
+// BN254OperatorInfoWitness identifies a non-signing operator in a BN254
+// certificate.
+type BN254OperatorInfoWitness struct {
+	OperatorIndex     uint32
+	OperatorInfoProof []byte
+	OperatorInfo      BN254OperatorInfo
+}
+
+// BN254Certificate mirrors IBN254CertificateVerifierTypes.BN254Certificate.
+type BN254Certificate struct {
+	ReferenceTimestamp uint32
+	MessageHash        [32]byte
+	Signature          bn254.G1Point
+	Apk                bn254.G2Point
+	NonSignerWitnesses []BN254OperatorInfoWitness
+}
+
+// ECDSACertificate mirrors IECDSACertificateVerifierTypes.ECDSACertificate.
+// Sig is the concatenation of 65 byte signatures ordered by signer address.
+type ECDSACertificate struct {
+	ReferenceTimestamp uint32
+	MessageHash        [32]byte
+	Sig                []byte
+}
+
+// DecodeBN254 decodes executorCert bytes produced by getBN254CertificateBytes.
+func DecodeBN254(data []byte) (*BN254Certificate, error) {
+	out, err := bn254CertArgs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BN254 certificate: %w", err)
+	}
+	return abi.ConvertType(out[0], new(BN254Certificate)).(*BN254Certificate), nil
+}
+
+// EncodeBN254 is the inverse of DecodeBN254.
+func EncodeBN254(cert *BN254Certificate) ([]byte, error) {
+	return bn254CertArgs.Pack(cert)
+}
+
+// DecodeECDSA decodes executorCert bytes produced by getECDSACertificateBytes.
+func DecodeECDSA(data []byte) (*ECDSACertificate, error) {
+	out, err := ecdsaCertArgs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECDSA certificate: %w", err)
+	}
+	return abi.ConvertType(out[0], new(ECDSACertificate)).(*ECDSACertificate), nil
+}
+
+// EncodeECDSA is the inverse of DecodeECDSA.
+func EncodeECDSA(cert *ECDSACertificate) ([]byte, error) {
+	return ecdsaCertArgs.Pack(cert)
+}
+
+// Result is the outcome of verifying a certificate against an operator set.
+// Signers and NonSigners are indexes into the operator list it was checked
+// against.
+type Result struct {
+	Valid         bool
+	Signers       []int
+	NonSigners    []int
+	SignedWeights []*big.Int
+	TotalWeights  []*big.Int
+}
+
+// MeetsThreshold reports whether the certificate is valid and the signers
+// hold at least thresholdBps basis points of every weight type.
+func (r *Result) MeetsThreshold(thresholdBps uint16) bool {
+	if !r.Valid || len(r.TotalWeights) == 0 {
+		return false
+	}
+	for i, total := range r.TotalWeights {
+		signed := new(big.Int).Mul(r.SignedWeights[i], big.NewInt(10000))
+		required := new(big.Int).Mul(total, big.NewInt(int64(thresholdBps)))
+		if signed.Cmp(required) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sumWeights adds weights into total, growing it as needed.
+func sumWeights(total []*big.Int, weights []*big.Int) []*big.Int {
+	total = padWeights(total, len(weights))
+	for i, w := range weights {
+		total[i].Add(total[i], w)
+	}
+	return total
+}
+
+// padWeights extends weights with zeros to n entries.
+func padWeights(weights []*big.Int, n int) []*big.Int {
+	for len(weights) < n {
+		weights = append(weights, new(big.Int))
+	}
+	return weights
+}
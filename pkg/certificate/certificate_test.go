@@ -0,0 +1,157 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/bn254"
+	"github.com/ethereum/go-ethereum/crypto"
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+func Test_BN254Certificate(t *testing.T) {
+	digest := crypto.Keccak256Hash([]byte("result"))
+
+	// Three operators; operators 0 and 2 sign
+	var operators []BN254OperatorInfo
+	var keys []*bn254.PrivateKey
+	for i := int64(1); i <= 3; i++ {
+		key, err := bn254.NewPrivateKey(big.NewInt(i * 7919).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		operators = append(operators, BN254OperatorInfo{Pubkey: key.PublicKeyG1(), Weights: []*big.Int{big.NewInt(i * 100)}})
+	}
+
+	var sigma *bn256.G1
+	var apkG2 *bn256.G2
+	for _, i := range []int{0, 2} {
+		sig, _ := keys[i].SignHash(digest)
+		s, _ := sig.G1()
+		pk2, _ := keys[i].PublicKeyG2().G2()
+		if sigma == nil {
+			sigma, apkG2 = s, pk2
+		} else {
+			sigma = new(bn256.G1).Add(sigma, s)
+			apkG2 = new(bn256.G2).Add(apkG2, pk2)
+		}
+	}
+
+	cert := &BN254Certificate{
+		ReferenceTimestamp: 1700000000,
+		MessageHash:        digest,
+		Signature:          bn254.NewG1Point(sigma),
+		Apk:                bn254.NewG2Point(apkG2),
+		NonSignerWitnesses: []BN254OperatorInfoWitness{{OperatorIndex: 1, OperatorInfoProof: []byte{}, OperatorInfo: operators[1]}},
+	}
+
+	data, err := EncodeBN254(cert)
+	if err != nil {
+		t.Fatalf("EncodeBN254 failed: %v", err)
+	}
+	decoded, err := DecodeBN254(data)
+	if err != nil {
+		t.Fatalf("DecodeBN254 failed: %v", err)
+	}
+	if decoded.ReferenceTimestamp != cert.ReferenceTimestamp || len(decoded.NonSignerWitnesses) != 1 {
+		t.Fatalf("certificate did not round trip: %+v", decoded)
+	}
+
+	result, err := VerifyBN254(decoded, decoded.MessageHash, operators)
+	if err != nil {
+		t.Fatalf("VerifyBN254 failed: %v", err)
+	}
+	if !result.Valid {
+		t.Fatal("expected a valid certificate")
+	}
+	if len(result.Signers) != 2 || len(result.NonSigners) != 1 || result.NonSigners[0] != 1 {
+		t.Fatalf("unexpected signers %v and non-signers %v", result.Signers, result.NonSigners)
+	}
+	// 400 of 600 signed
+	if !result.MeetsThreshold(6666) || result.MeetsThreshold(6667) {
+		t.Fatalf("unexpected threshold result for %s of %s", result.SignedWeights[0], result.TotalWeights[0])
+	}
+
+	// Omitting the non-signer makes the aggregate key wrong
+	decoded.NonSignerWitnesses = nil
+	result, err = VerifyBN254(decoded, decoded.MessageHash, operators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid {
+		t.Fatal("expected an invalid certificate without the non-signer witness")
+	}
+}
+
+func Test_ECDSACertificate(t *testing.T) {
+	digest := crypto.Keccak256Hash([]byte("result"))
+
+	var keys []*ecdsa.PrivateKey
+	var operators []ECDSAOperatorInfo
+	for i := int64(1); i <= 3; i++ {
+		key, err := crypto.ToECDSA(keyBytes(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		operators = append(operators, ECDSAOperatorInfo{Pubkey: crypto.PubkeyToAddress(key.PublicKey), Weights: []*big.Int{big.NewInt(1)}})
+	}
+
+	// Two signers, signatures ordered by address
+	signers := []*ecdsa.PrivateKey{keys[0], keys[1]}
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(crypto.PubkeyToAddress(signers[i].PublicKey).Bytes(), crypto.PubkeyToAddress(signers[j].PublicKey).Bytes()) < 0
+	})
+	var sig []byte
+	for _, key := range signers {
+		s, err := crypto.Sign(digest[:], key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s[crypto.RecoveryIDOffset] += 27
+		sig = append(sig, s...)
+	}
+
+	data, err := EncodeECDSA(&ECDSACertificate{ReferenceTimestamp: 1, MessageHash: digest, Sig: sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := DecodeECDSA(data)
+	if err != nil {
+		t.Fatalf("DecodeECDSA failed: %v", err)
+	}
+
+	result, err := VerifyECDSA(cert, cert.MessageHash, operators)
+	if err != nil {
+		t.Fatalf("VerifyECDSA failed: %v", err)
+	}
+	if !result.Valid || len(result.Signers) != 2 || len(result.NonSigners) != 1 || result.NonSigners[0] != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if !result.MeetsThreshold(6666) || result.MeetsThreshold(6667) {
+		t.Fatal("unexpected threshold result")
+	}
+
+	// Reversed order is rejected
+	reversed := append(append([]byte{}, sig[65:]...), sig[:65]...)
+	if _, err := VerifyECDSA(&ECDSACertificate{Sig: reversed}, digest, operators); err == nil {
+		t.Fatal("expected an error for unordered signatures")
+	}
+
+	// A signer outside the operator table invalidates the certificate
+	result, err = VerifyECDSA(cert, cert.MessageHash, operators[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid {
+		t.Fatal("expected an invalid certificate for unknown signers")
+	}
+}
+
+func keyBytes(i int64) []byte {
+	return big.NewInt(i).FillBytes(make([]byte, 32))
+}
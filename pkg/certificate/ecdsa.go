@@ -0,0 +1,84 @@
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ECDSAOperatorInfo is an operator's entry in an ECDSA operator table.
+type ECDSAOperatorInfo struct {
+	Pubkey  common.Address
+	Weights []*big.Int
+}
+
+const ecdsaSignatureLength = 65
+
+// VerifyECDSA checks cert against the operator table operators. digest is the
+// message the operators signed; pass cert.MessageHash unless the verifier
+// contract signs a derived digest (ECDSACertificateVerifier.calculateCertificateDigest).
+//
+// As on-chain, signatures must be ordered by strictly increasing signer
+// address and every signer must be in the operator table. An invalid
+// signature makes the whole certificate invalid.
+func VerifyECDSA(cert *ECDSACertificate, digest [32]byte, operators []ECDSAOperatorInfo) (*Result, error) {
+	if len(cert.Sig) == 0 || len(cert.Sig)%ecdsaSignatureLength != 0 {
+		return nil, fmt.Errorf("signature length %d is not a multiple of %d", len(cert.Sig), ecdsaSignatureLength)
+	}
+
+	index := make(map[common.Address]int, len(operators))
+	for i, op := range operators {
+		index[op.Pubkey] = i
+	}
+
+	result := &Result{Valid: true}
+	signed := make(map[int]bool)
+	var previous common.Address
+	for offset := 0; offset < len(cert.Sig); offset += ecdsaSignatureLength {
+		signer, err := recoverSigner(digest, cert.Sig[offset:offset+ecdsaSignatureLength])
+		if err != nil {
+			result.Valid = false
+			break
+		}
+		if offset > 0 && bytes.Compare(signer.Bytes(), previous.Bytes()) <= 0 {
+			return nil, fmt.Errorf("signers are not in ascending address order at %s", signer)
+		}
+		previous = signer
+
+		i, ok := index[signer]
+		if !ok {
+			// A signer outside the operator set invalidates the certificate
+			result.Valid = false
+			break
+		}
+		signed[i] = true
+	}
+
+	for i, op := range operators {
+		result.TotalWeights = sumWeights(result.TotalWeights, op.Weights)
+		if signed[i] {
+			result.Signers = append(result.Signers, i)
+			result.SignedWeights = sumWeights(result.SignedWeights, op.Weights)
+		} else {
+			result.NonSigners = append(result.NonSigners, i)
+		}
+	}
+	result.SignedWeights = padWeights(result.SignedWeights, len(result.TotalWeights))
+	return result, nil
+}
+
+func recoverSigner(digest [32]byte, sig []byte) (common.Address, error) {
+	normalized := make([]byte, ecdsaSignatureLength)
+	copy(normalized, sig)
+	if normalized[crypto.RecoveryIDOffset] >= 27 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(digest[:], normalized)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
@@ -0,0 +1,191 @@
+// Package client sends TaskRequests to a running Performer over gRPC, exactly
+// as the Executor does, and collects latency and error statistics.
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// PerformerClient is a gRPC client for the Performer service.
+type PerformerClient struct {
+	conn    *grpc.ClientConn
+	client  performerV1.PerformerServiceClient
+	timeout time.Duration
+}
+
+// NewPerformerClient connects to a Performer at addr, e.g. "localhost:8080".
+// Each request is bounded by timeout; zero disables the per-request timeout.
+func NewPerformerClient(addr string, timeout time.Duration) (*PerformerClient, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client for %s: %w", addr, err)
+	}
+	return &PerformerClient{
+		conn:    conn,
+		client:  performerV1.NewPerformerServiceClient(conn),
+		timeout: timeout,
+	}, nil
+}
+
+func (c *PerformerClient) Close() error {
+	return c.conn.Close()
+}
+
+// ExecuteTask sends a single task and returns the response and latency.
+func (c *PerformerClient) ExecuteTask(ctx context.Context, task *performerV1.TaskRequest) (*performerV1.TaskResponse, time.Duration, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	resp, err := c.client.ExecuteTask(ctx, task)
+	return resp, time.Since(start), err
+}
+
+// Result is the outcome of a single task sent by Run.
+type Result struct {
+	Index    int
+	Task     *performerV1.TaskRequest
+	Response *performerV1.TaskResponse
+	Latency  time.Duration
+	Err      error
+}
+
+// Run sends tasks using concurrency workers. If total exceeds len(tasks) the
+// tasks are repeated, with next building the request for each send. onResult
+// is called from the worker goroutines and must be safe for concurrent use.
+func (c *PerformerClient) Run(
+	ctx context.Context,
+	total int,
+	concurrency int,
+	next func(i int) *performerV1.TaskRequest,
+	onResult func(Result),
+) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				task := next(i)
+				resp, latency, err := c.ExecuteTask(ctx, task)
+				onResult(Result{Index: i, Task: task, Response: resp, Latency: latency, Err: err})
+			}
+		}()
+	}
+
+	for i := 0; i < total; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			i = total
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// Stats aggregates latencies and errors across many sends. It is safe for
+// concurrent use.
+type Stats struct {
+	mu        sync.Mutex
+	start     time.Time
+	latencies []time.Duration
+	errors    map[string]int
+}
+
+func NewStats() *Stats {
+	return &Stats{start: time.Now(), errors: make(map[string]int)}
+}
+
+// Record adds a result, grouping errors by gRPC status code.
+func (s *Stats) Record(r Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latencies = append(s.latencies, r.Latency)
+	if r.Err != nil {
+		s.errors[status.Code(r.Err).String()]++
+	}
+}
+
+// Summary is a point-in-time report of Stats.
+type Summary struct {
+	Requests   int            `json:"requests"`
+	Succeeded  int            `json:"succeeded"`
+	Failed     int            `json:"failed"`
+	Errors     map[string]int `json:"errors,omitempty"`
+	Elapsed    time.Duration  `json:"elapsedNs"`
+	Throughput float64        `json:"throughputPerSecond"`
+	Min        time.Duration  `json:"minNs"`
+	Mean       time.Duration  `json:"meanNs"`
+	P50        time.Duration  `json:"p50Ns"`
+	P90        time.Duration  `json:"p90Ns"`
+	P99        time.Duration  `json:"p99Ns"`
+	Max        time.Duration  `json:"maxNs"`
+}
+
+func (s *Stats) Summary() Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sum := Summary{
+		Requests: len(s.latencies),
+		Errors:   make(map[string]int, len(s.errors)),
+		Elapsed:  time.Since(s.start),
+	}
+	for code, n := range s.errors {
+		sum.Errors[code] = n
+		sum.Failed += n
+	}
+	sum.Succeeded = sum.Requests - sum.Failed
+	if sum.Elapsed > 0 {
+		sum.Throughput = float64(sum.Requests) / sum.Elapsed.Seconds()
+	}
+	if len(s.latencies) == 0 {
+		return sum
+	}
+
+	sorted := append([]time.Duration(nil), s.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+	sum.Min = sorted[0]
+	sum.Max = sorted[len(sorted)-1]
+	sum.Mean = total / time.Duration(len(sorted))
+	sum.P50 = percentile(sorted, 0.50)
+	sum.P90 = percentile(sorted, 0.90)
+	sum.P99 = percentile(sorted, 0.99)
+	return sum
+}
+
+// percentile uses the nearest-rank method on sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testPerformer struct {
+	performerV1.UnimplementedPerformerServiceServer
+	calls atomic.Int64
+}
+
+func (p *testPerformer) ExecuteTask(_ context.Context, t *performerV1.TaskRequest) (*performerV1.TaskResponse, error) {
+	p.calls.Add(1)
+	if string(t.Payload) == "fail" {
+		return nil, status.Error(codes.InvalidArgument, "bad payload")
+	}
+	return &performerV1.TaskResponse{TaskId: t.TaskId, Result: t.Payload}, nil
+}
+
+func Test_PerformerClientRun(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	performer := &testPerformer{}
+	performerV1.RegisterPerformerServiceServer(srv, performer)
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	c, err := NewPerformerClient(lis.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewPerformerClient failed: %v", err)
+	}
+	defer c.Close()
+
+	resp, _, err := c.ExecuteTask(context.Background(), &performerV1.TaskRequest{TaskId: []byte("1"), Payload: []byte("echo")})
+	if err != nil {
+		t.Fatalf("ExecuteTask failed: %v", err)
+	}
+	if string(resp.Result) != "echo" {
+		t.Errorf("expected echoed result, got %q", resp.Result)
+	}
+
+	payloads := []string{"ok", "fail"}
+	stats := NewStats()
+	c.Run(context.Background(), 10, 3, func(i int) *performerV1.TaskRequest {
+		return &performerV1.TaskRequest{TaskId: []byte{byte(i)}, Payload: []byte(payloads[i%len(payloads)])}
+	}, stats.Record)
+
+	sum := stats.Summary()
+	if sum.Requests != 10 || sum.Succeeded != 5 || sum.Failed != 5 {
+		t.Errorf("unexpected summary: %+v", sum)
+	}
+	if sum.Errors[codes.InvalidArgument.String()] != 5 {
+		t.Errorf("expected errors grouped by code, got %v", sum.Errors)
+	}
+	if sum.Min > sum.P50 || sum.P50 > sum.P99 || sum.P99 > sum.Max {
+		t.Errorf("latency percentiles are not ordered: %+v", sum)
+	}
+	if got := performer.calls.Load(); got != 11 {
+		t.Errorf("expected 11 calls to the performer, got %d", got)
+	}
+}
@@ -0,0 +1,247 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RPCCall is a JSON-RPC request observed by a RecordingTransport.
+type RPCCall struct {
+	Method string `json:"method"`
+	// Block is the block the call reads from: a number, a hash, a tag such as
+	// "latest", or empty if the method reads the chain head implicitly.
+	Block string `json:"block"`
+}
+
+// ReadsLatest reports whether the call reads state at a moving block, which
+// makes results depend on when each operator happens to run the task.
+func (c RPCCall) ReadsLatest() bool {
+	switch c.Block {
+	case "", "latest", "pending":
+		return true
+	}
+	return false
+}
+
+// blockParam is the index of the block parameter for state reading methods.
+// Methods not listed here do not read state at a block.
+var blockParam = map[string]int{
+	"eth_blockNumber":         -1,
+	"eth_call":                1,
+	"eth_estimateGas":         1,
+	"eth_createAccessList":    1,
+	"eth_getBalance":          1,
+	"eth_getCode":             1,
+	"eth_getTransactionCount": 1,
+	"eth_getStorageAt":        2,
+	"eth_getProof":            2,
+	"eth_getBlockByNumber":    0,
+}
+
+// RecordingTransport is an http.RoundTripper that records the state reads
+// made by an RPC client.
+type RecordingTransport struct {
+	Next http.RoundTripper
+
+	mu    sync.Mutex
+	calls []RPCCall
+}
+
+type jsonRPCRequest struct {
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		t.record(body)
+	}
+
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
+
+func (t *RecordingTransport) record(body []byte) {
+	var batch []jsonRPCRequest
+	if err := json.Unmarshal(body, &batch); err != nil {
+		var single jsonRPCRequest
+		if err := json.Unmarshal(body, &single); err != nil {
+			return
+		}
+		batch = []jsonRPCRequest{single}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range batch {
+		idx, ok := blockParam[r.Method]
+		if !ok {
+			continue
+		}
+		call := RPCCall{Method: r.Method}
+		if idx >= 0 && idx < len(r.Params) {
+			call.Block = blockString(r.Params[idx])
+		}
+		t.calls = append(t.calls, call)
+	}
+}
+
+// blockString normalizes a block parameter, which is either a tag/number
+// string or an EIP-1898 object.
+func blockString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var obj struct {
+		BlockHash   string `json:"blockHash"`
+		BlockNumber string `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		if obj.BlockHash != "" {
+			return obj.BlockHash
+		}
+		return obj.BlockNumber
+	}
+	return ""
+}
+
+// Calls returns the state reads recorded so far.
+func (t *RecordingTransport) Calls() []RPCCall {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]RPCCall(nil), t.calls...)
+}
+
+// LatestReads returns the recorded reads that are not pinned to a block.
+func (t *RecordingTransport) LatestReads() []RPCCall {
+	var out []RPCCall
+	for _, c := range t.Calls() {
+		if c.ReadsLatest() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// PinnedTransport is an http.RoundTripper that pins the state reads of an RPC
+// client to Block. Reads of "latest", "pending", "safe" or "finalized", or
+// with no block at all, are rewritten to read Block instead, and
+// eth_blockNumber is answered with Block. Reads of an explicit block are left
+// alone.
+type PinnedTransport struct {
+	Next  http.RoundTripper
+	Block uint64
+}
+
+type pinnedRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+func (t *PinnedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Body == nil {
+		return next.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+
+	block, err := json.Marshal(hexutil.EncodeUint64(t.Block))
+	if err != nil {
+		return nil, err
+	}
+
+	var batch []pinnedRequest
+	if err := json.Unmarshal(body, &batch); err == nil {
+		for i := range batch {
+			batch[i].pin(block)
+		}
+		body, err = json.Marshal(batch)
+	} else {
+		var single pinnedRequest
+		if err := json.Unmarshal(body, &single); err != nil {
+			// Not JSON-RPC; pass it through unchanged
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			return next.RoundTrip(req)
+		}
+		// ethclient never batches eth_blockNumber, so only single requests
+		// are answered here.
+		if single.Method == "eth_blockNumber" {
+			return t.respond(req, single.ID, block)
+		}
+		single.pin(block)
+		body, err = json.Marshal(single)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return next.RoundTrip(req)
+}
+
+// pin rewrites r's block parameter to block if it reads a moving block.
+func (r *pinnedRequest) pin(block json.RawMessage) {
+	idx, ok := blockParam[r.Method]
+	if !ok || idx < 0 {
+		return
+	}
+	if idx >= len(r.Params) {
+		// Only a missing trailing block parameter can be filled in
+		if idx == len(r.Params) {
+			r.Params = append(r.Params, block)
+		}
+		return
+	}
+	switch blockString(r.Params[idx]) {
+	case "", "latest", "pending", "safe", "finalized":
+		r.Params[idx] = block
+	}
+}
+
+func (t *PinnedTransport) respond(req *http.Request, id json.RawMessage, result json.RawMessage) (*http.Response, error) {
+	body, err := json.Marshal(struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  json.RawMessage `json:"result"`
+	}{"2.0", id, result})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
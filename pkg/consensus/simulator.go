@@ -0,0 +1,232 @@
+// Package consensus simulates several operators running the same task and
+// checks that they agree on the result. The Aggregator only certifies a result
+// once enough operators sign the same bytes, so any nondeterminism in
+// HandleTask turns into failed tasks on the network.
+package consensus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Performer is the part of the TaskWorker exercised by the simulator.
+type Performer interface {
+	ValidateTask(*performerV1.TaskRequest) error
+	HandleTask(*performerV1.TaskRequest) (*performerV1.TaskResponse, error)
+}
+
+// Operator describes one simulated operator.
+type Operator struct {
+	Name        string
+	L1RpcUrl    string
+	L2RpcUrl    string
+	ClockOffset time.Duration
+}
+
+// Factory builds an independent Performer for op. The Performer must use clock
+// as its time source and send its RPC traffic through transport. If the
+// Performer implements io.Closer, it is closed once its run is done.
+type Factory func(op Operator, clock func() time.Time, transport http.RoundTripper) (Performer, error)
+
+// Source is a class of nondeterminism the simulator can identify.
+type Source string
+
+const (
+	// SourceRandomness means a single operator produced different results for
+	// the same task under identical conditions, e.g. map iteration order or
+	// math/rand.
+	SourceRandomness Source = "randomness or map iteration order"
+	// SourceTime means shifting the clock changed the result.
+	SourceTime Source = "time"
+	// SourceLatestBlock means the task reads chain state at a moving block.
+	SourceLatestBlock Source = "latest-block reads"
+	// SourceRPC means operators using different RPC endpoints disagree.
+	SourceRPC Source = "RPC endpoint state"
+)
+
+// Finding is a detected source of divergence.
+type Finding struct {
+	Source Source `json:"source"`
+	Detail string `json:"detail"`
+}
+
+// Outcome is the result of a single run of the task.
+type Outcome struct {
+	Operator    string      `json:"operator"`
+	Probe       string      `json:"probe"`
+	Result      string      `json:"result,omitempty"`
+	ResultHash  common.Hash `json:"resultHash"`
+	Error       string      `json:"error,omitempty"`
+	LatestReads []RPCCall   `json:"latestReads,omitempty"`
+}
+
+// Report summarizes a simulation.
+type Report struct {
+	Operators int       `json:"operators"`
+	Agreed    bool      `json:"agreed"`
+	Outcomes  []Outcome `json:"outcomes"`
+	Findings  []Finding `json:"findings,omitempty"`
+}
+
+// Simulator runs a task through independent Performers, one per operator.
+type Simulator struct {
+	Factory   Factory
+	Operators []Operator
+
+	// Repeats is the number of additional runs per operator under identical
+	// conditions, used to detect randomness.
+	Repeats int
+
+	// ClockSkew shifts the clock of an extra run of the first operator, used to
+	// detect time dependence. Zero disables the probe.
+	ClockSkew time.Duration
+
+	// BaseTime is the frozen time every simulated clock starts from. Zero uses
+	// the time Run is called.
+	BaseTime time.Time
+}
+
+// Run executes task on every operator and reports whether they agree and, if
+// not, what caused the divergence.
+func (s *Simulator) Run(task *performerV1.TaskRequest) (*Report, error) {
+	if len(s.Operators) == 0 {
+		return nil, fmt.Errorf("no operators to simulate")
+	}
+	base := s.BaseTime
+	if base.IsZero() {
+		base = time.Now()
+	}
+
+	report := &Report{Operators: len(s.Operators)}
+	var primary []Outcome
+	for _, op := range s.Operators {
+		o, err := s.runOnce(op, "primary", base.Add(op.ClockOffset), task)
+		if err != nil {
+			return nil, err
+		}
+		primary = append(primary, o)
+		report.Outcomes = append(report.Outcomes, o)
+	}
+	report.Agreed = agree(primary)
+
+	// Identical conditions must give identical results
+	random := false
+	for i, op := range s.Operators {
+		diverged := false
+		for r := 0; r < s.Repeats; r++ {
+			o, err := s.runOnce(op, fmt.Sprintf("repeat-%d", r+1), base.Add(op.ClockOffset), task)
+			if err != nil {
+				return nil, err
+			}
+			report.Outcomes = append(report.Outcomes, o)
+			diverged = diverged || o.ResultHash != primary[i].ResultHash
+		}
+		if diverged {
+			random = true
+			report.Findings = append(report.Findings, Finding{
+				Source: SourceRandomness,
+				Detail: fmt.Sprintf("operator %s produced different results for identical runs", op.Name),
+			})
+		}
+	}
+
+	// Only the clock changes between these two runs
+	if s.ClockSkew != 0 {
+		op := s.Operators[0]
+		o, err := s.runOnce(op, "clock-skew", base.Add(op.ClockOffset+s.ClockSkew), task)
+		if err != nil {
+			return nil, err
+		}
+		report.Outcomes = append(report.Outcomes, o)
+		if o.ResultHash != primary[0].ResultHash && !random {
+			report.Findings = append(report.Findings, Finding{
+				Source: SourceTime,
+				Detail: fmt.Sprintf("shifting the clock by %s changed the result", s.ClockSkew),
+			})
+		}
+	}
+
+	// Reads at a moving block are a risk even if the operators happened to agree
+	reads := make(map[string]int)
+	for _, o := range primary {
+		for _, c := range o.LatestReads {
+			reads[c.Method]++
+		}
+	}
+	if len(reads) > 0 {
+		methods := make([]string, 0, len(reads))
+		for m := range reads {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+		report.Findings = append(report.Findings, Finding{
+			Source: SourceLatestBlock,
+			Detail: fmt.Sprintf("task reads state without pinning a block: %v; pin reads to a block derived from the task", methods),
+		})
+	}
+
+	// Disagreement not explained by the probes above comes from the endpoints
+	if !report.Agreed && len(report.Findings) == 0 && distinctEndpoints(s.Operators) {
+		report.Findings = append(report.Findings, Finding{
+			Source: SourceRPC,
+			Detail: "operators using different RPC endpoints disagree; the endpoints may be at different heights or serve different state",
+		})
+	}
+	return report, nil
+}
+
+func (s *Simulator) runOnce(op Operator, probe string, at time.Time, task *performerV1.TaskRequest) (Outcome, error) {
+	rec := &RecordingTransport{}
+	p, err := s.Factory(op, func() time.Time { return at }, rec)
+	if err != nil {
+		return Outcome{}, fmt.Errorf("failed to create performer for operator %s: %w", op.Name, err)
+	}
+	if c, ok := p.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	o := Outcome{Operator: op.Name, Probe: probe}
+	var result []byte
+	if err := p.ValidateTask(task); err != nil {
+		o.Error = fmt.Sprintf("validation failed: %v", err)
+	} else if resp, err := p.HandleTask(task); err != nil {
+		o.Error = fmt.Sprintf("handling failed: %v", err)
+	} else {
+		result = resp.Result
+		o.Result = common.Bytes2Hex(result)
+	}
+
+	// Errors are part of the outcome: an operator that fails does not sign
+	if o.Error != "" {
+		o.ResultHash = crypto.Keccak256Hash([]byte("error:" + o.Error))
+	} else {
+		o.ResultHash = crypto.Keccak256Hash(result)
+	}
+	o.LatestReads = rec.LatestReads()
+	return o, nil
+}
+
+func agree(outcomes []Outcome) bool {
+	for _, o := range outcomes[1:] {
+		if o.ResultHash != outcomes[0].ResultHash {
+			return false
+		}
+	}
+	return true
+}
+
+func distinctEndpoints(ops []Operator) bool {
+	for _, op := range ops[1:] {
+		if op.L1RpcUrl != ops[0].L1RpcUrl || op.L2RpcUrl != ops[0].L2RpcUrl {
+			return true
+		}
+	}
+	return false
+}
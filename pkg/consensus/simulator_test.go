@@ -0,0 +1,238 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+)
+
+type funcPerformer func(t *performerV1.TaskRequest) []byte
+
+func (f funcPerformer) ValidateTask(*performerV1.TaskRequest) error { return nil }
+
+func (f funcPerformer) HandleTask(t *performerV1.TaskRequest) (*performerV1.TaskResponse, error) {
+	return &performerV1.TaskResponse{TaskId: t.TaskId, Result: f(t)}, nil
+}
+
+// closingPerformer counts how often it is closed.
+type closingPerformer struct {
+	funcPerformer
+	closed *int
+}
+
+func (p closingPerformer) Close() error {
+	*p.closed++
+	return nil
+}
+
+func operators(n int, endpoints ...string) []Operator {
+	ops := make([]Operator, n)
+	for i := range ops {
+		ops[i] = Operator{Name: fmt.Sprintf("op-%d", i), ClockOffset: time.Duration(i) * time.Second}
+		if len(endpoints) > 0 {
+			ops[i].L2RpcUrl = endpoints[i%len(endpoints)]
+		}
+	}
+	return ops
+}
+
+func sources(r *Report) []Source {
+	var out []Source
+	for _, f := range r.Findings {
+		out = append(out, f.Source)
+	}
+	return out
+}
+
+func Test_SimulatorSources(t *testing.T) {
+	task := &performerV1.TaskRequest{TaskId: []byte("task"), Payload: []byte("payload")}
+
+	tests := []struct {
+		name       string
+		factory    Factory
+		endpoints  []string
+		wantAgreed bool
+		want       []Source
+	}{
+		{
+			name: "deterministic",
+			factory: func(Operator, func() time.Time, http.RoundTripper) (Performer, error) {
+				return funcPerformer(func(t *performerV1.TaskRequest) []byte { return t.Payload }), nil
+			},
+			wantAgreed: true,
+		},
+		{
+			name: "randomness",
+			factory: func(Operator, func() time.Time, http.RoundTripper) (Performer, error) {
+				return funcPerformer(func(*performerV1.TaskRequest) []byte {
+					return binary.BigEndian.AppendUint64(nil, rand.Uint64())
+				}), nil
+			},
+			wantAgreed: false,
+			want:       []Source{SourceRandomness, SourceRandomness, SourceRandomness},
+		},
+		{
+			name: "time",
+			factory: func(_ Operator, clock func() time.Time, _ http.RoundTripper) (Performer, error) {
+				return funcPerformer(func(*performerV1.TaskRequest) []byte {
+					return []byte(clock().Format(time.RFC3339))
+				}), nil
+			},
+			wantAgreed: false,
+			want:       []Source{SourceTime},
+		},
+		{
+			name:      "rpc endpoints",
+			endpoints: []string{"http://a", "http://b"},
+			factory: func(op Operator, _ func() time.Time, _ http.RoundTripper) (Performer, error) {
+				return funcPerformer(func(*performerV1.TaskRequest) []byte { return []byte(op.L2RpcUrl) }), nil
+			},
+			wantAgreed: false,
+			want:       []Source{SourceRPC},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := &Simulator{
+				Factory:   tt.factory,
+				Operators: operators(3, tt.endpoints...),
+				Repeats:   2,
+				ClockSkew: time.Hour,
+			}
+			report, err := sim.Run(task)
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if report.Agreed != tt.wantAgreed {
+				t.Errorf("expected agreed=%v, got %v", tt.wantAgreed, report.Agreed)
+			}
+			if fmt.Sprint(sources(report)) != fmt.Sprint(tt.want) {
+				t.Errorf("expected findings %v, got %v", tt.want, report.Findings)
+			}
+		})
+	}
+}
+
+func Test_SimulatorClosesPerformers(t *testing.T) {
+	var built, closed int
+	sim := &Simulator{
+		Factory: func(Operator, func() time.Time, http.RoundTripper) (Performer, error) {
+			built++
+			return closingPerformer{
+				funcPerformer: func(t *performerV1.TaskRequest) []byte { return t.Payload },
+				closed:        &closed,
+			}, nil
+		},
+		Operators: operators(3),
+		Repeats:   2,
+		ClockSkew: time.Hour,
+	}
+	if _, err := sim.Run(&performerV1.TaskRequest{TaskId: []byte("task"), Payload: []byte("payload")}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if built == 0 || closed != built {
+		t.Errorf("closed %d of %d performers", closed, built)
+	}
+}
+
+type echoTransport struct{}
+
+func (echoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+}
+
+func Test_RecordingTransport(t *testing.T) {
+	rec := &RecordingTransport{Next: echoTransport{}}
+
+	bodies := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"to":"0x01"},"latest"]}`,
+		`{"jsonrpc":"2.0","id":2,"method":"eth_call","params":[{"to":"0x01"},"0x10"]}`,
+		`[{"jsonrpc":"2.0","id":3,"method":"eth_getStorageAt","params":["0x01","0x0",{"blockHash":"0xabc"}]},
+		  {"jsonrpc":"2.0","id":4,"method":"eth_blockNumber","params":[]},
+		  {"jsonrpc":"2.0","id":5,"method":"eth_chainId","params":[]}]`,
+	}
+	for _, b := range bodies {
+		req, err := http.NewRequest(http.MethodPost, "http://rpc", bytes.NewBufferString(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rec.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip failed: %v", err)
+		}
+	}
+
+	if got := len(rec.Calls()); got != 4 {
+		t.Errorf("expected 4 state reads, got %d: %v", got, rec.Calls())
+	}
+	latest := rec.LatestReads()
+	if len(latest) != 2 || latest[0].Method != "eth_call" || latest[1].Method != "eth_blockNumber" {
+		t.Errorf("unexpected latest reads: %v", latest)
+	}
+}
+
+// bodyTransport records the bodies of the requests it receives.
+type bodyTransport struct {
+	bodies []string
+}
+
+func (b *bodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	b.bodies = append(b.bodies, string(body))
+	return echoTransport{}.RoundTrip(req)
+}
+
+func Test_PinnedTransport(t *testing.T) {
+	next := &bodyTransport{}
+	pinned := &PinnedTransport{Next: next, Block: 16}
+
+	send := func(body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, "http://rpc", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := pinned.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip failed: %v", err)
+		}
+		return resp
+	}
+
+	send(`{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"to":"0x01"},"latest"]}`)
+	send(`[{"jsonrpc":"2.0","id":2,"method":"eth_getBalance","params":["0x01"]},
+	       {"jsonrpc":"2.0","id":3,"method":"eth_getCode","params":["0x01","0x5"]},
+	       {"jsonrpc":"2.0","id":4,"method":"eth_chainId","params":[]}]`)
+
+	rec := &RecordingTransport{}
+	for _, body := range next.bodies {
+		rec.record([]byte(body))
+	}
+	calls := rec.Calls()
+	if len(calls) != 3 || calls[0].Block != "0x10" || calls[1].Block != "0x10" || calls[2].Block != "0x5" {
+		t.Errorf("reads were not pinned to block 16: %v", calls)
+	}
+
+	resp := send(`{"jsonrpc":"2.0","id":5,"method":"eth_blockNumber","params":[]}`)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"jsonrpc":"2.0","id":5,"result":"0x10"}` {
+		t.Errorf("unexpected eth_blockNumber response %s", body)
+	}
+	if len(next.bodies) != 2 {
+		t.Errorf("eth_blockNumber should be answered locally, %d requests were sent", len(next.bodies))
+	}
+}
@@ -0,0 +1,231 @@
+// Package deployments loads contract addresses from the deploy output files
+// that devkit writes to .devkit/contracts/script/<environment>/output, and
+// merges them with the addresses the executor passes as environment variables.
+package deployments
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultDir is where devkit writes deploy outputs, relative to the project root.
+const DefaultDir = ".devkit/contracts/script"
+
+// Precedence decides which source wins when a contract is both in the
+// environment and in a deploy output file.
+type Precedence string
+
+const (
+	// PreferEnv keeps addresses set by the executor, so a deployed Performer
+	// is never redirected by stale local files. This is the default.
+	PreferEnv Precedence = "env"
+	// PreferFiles uses the deploy outputs, e.g. after redeploying to a devnet
+	// while old addresses are still exported in the shell.
+	PreferFiles Precedence = "files"
+)
+
+// Sources of an address, as reported by Store.Source.
+const (
+	SourceEnv = "env"
+)
+
+var (
+	lowerUpper = regexp.MustCompile("([a-z0-9])([A-Z])")
+	acronym    = regexp.MustCompile("([A-Z]+)([A-Z][a-z])")
+)
+
+// EnvName converts a contract name to the environment variable name the
+// executor template uses for its address, e.g. taskAVSRegistrar becomes
+// TASK_AVS_REGISTRAR.
+func EnvName(name string) string {
+	name = lowerUpper.ReplaceAllString(name, "${1}_${2}")
+	name = acronym.ReplaceAllString(name, "${1}_${2}")
+	return strings.ToUpper(name)
+}
+
+// Config selects the deploy outputs to load. Loading is off unless
+// Environment is set.
+type Config struct {
+	// Dir contains one directory per environment.
+	Dir string
+	// Environment is the devkit environment, e.g. devnet.
+	Environment string
+	Precedence  Precedence
+}
+
+// ConfigFromEnv reads PERFORMER_CONTRACTS_ENV, PERFORMER_CONTRACTS_DIR and
+// PERFORMER_CONTRACTS_PRECEDENCE.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Dir:         os.Getenv("PERFORMER_CONTRACTS_DIR"),
+		Environment: os.Getenv("PERFORMER_CONTRACTS_ENV"),
+		Precedence:  Precedence(os.Getenv("PERFORMER_CONTRACTS_PRECEDENCE")),
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = DefaultDir
+	}
+	if cfg.Precedence == "" {
+		cfg.Precedence = PreferEnv
+	}
+	if err := cfg.Precedence.validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (p Precedence) validate() error {
+	switch p {
+	case PreferEnv, PreferFiles:
+		return nil
+	default:
+		return fmt.Errorf("invalid contract precedence %q: must be %q or %q", p, PreferEnv, PreferFiles)
+	}
+}
+
+// OutputDir is the directory holding the environment's deploy outputs.
+func (c Config) OutputDir() string {
+	return filepath.Join(c.Dir, c.Environment, "output")
+}
+
+// deployOutput is the layout of a devkit deploy output file.
+type deployOutput struct {
+	Addresses map[string]string `json:"addresses"`
+}
+
+// ReadOutputs reads every *.json file in dir and returns the addresses keyed
+// by environment variable name, with the file each one came from. Entries with
+// an empty name or address are placeholders and are skipped.
+func ReadOutputs(dir string) (map[string]common.Address, map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no deploy outputs in %s", dir)
+	}
+	sort.Strings(files)
+
+	addresses := make(map[string]common.Address)
+	sources := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		var out deployOutput
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		for name, value := range out.Addresses {
+			if name == "" || value == "" {
+				continue
+			}
+			if !common.IsHexAddress(value) {
+				return nil, nil, fmt.Errorf("%s: invalid address %q for %s", file, value, name)
+			}
+			envName := EnvName(name)
+			address := common.HexToAddress(value)
+			if prev, ok := addresses[envName]; ok && prev != address {
+				return nil, nil, fmt.Errorf("%s is %s in %s but %s in %s", envName, prev, sources[envName], address, file)
+			}
+			addresses[envName] = address
+			sources[envName] = file
+		}
+	}
+	return addresses, sources, nil
+}
+
+// Source is a set of contract addresses keyed by environment variable name.
+// The Performer's contracts.ContractStore implements it.
+type Source interface {
+	GetContract(name string) (common.Address, error)
+	ListContracts() []string
+}
+
+// Store is a merged view of contract addresses. It can be used in place of
+// contracts.ContractStore.
+type Store struct {
+	addresses map[string]common.Address
+	sources   map[string]string
+}
+
+// Load merges the addresses in env, which may be nil, with the deploy outputs
+// selected by cfg.
+func Load(cfg Config, env Source) (*Store, error) {
+	s := &Store{
+		addresses: make(map[string]common.Address),
+		sources:   make(map[string]string),
+	}
+	if env != nil {
+		for _, name := range env.ListContracts() {
+			if address, err := env.GetContract(name); err == nil {
+				s.addresses[name] = address
+				s.sources[name] = SourceEnv
+			}
+		}
+	}
+	if cfg.Environment == "" {
+		return s, nil
+	}
+
+	if cfg.Dir == "" {
+		cfg.Dir = DefaultDir
+	}
+	if cfg.Precedence == "" {
+		cfg.Precedence = PreferEnv
+	}
+	if err := cfg.Precedence.validate(); err != nil {
+		return nil, err
+	}
+
+	addresses, sources, err := ReadOutputs(cfg.OutputDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s deploy outputs: %w", cfg.Environment, err)
+	}
+	for name, address := range addresses {
+		if _, ok := s.addresses[name]; ok && cfg.Precedence == PreferEnv {
+			continue
+		}
+		s.addresses[name] = address
+		s.sources[name] = sources[name]
+	}
+	return s, nil
+}
+
+// GetContract returns the address of the contract with the given environment
+// variable name.
+func (s *Store) GetContract(name string) (common.Address, error) {
+	address, ok := s.addresses[name]
+	if !ok {
+		return common.Address{}, fmt.Errorf("contract %s not found", name)
+	}
+	return address, nil
+}
+
+// GetTaskAVSRegistrar returns the TaskAVSRegistrar address.
+func (s *Store) GetTaskAVSRegistrar() (common.Address, error) {
+	return s.GetContract("TASK_AVS_REGISTRAR")
+}
+
+// ListContracts returns the names of all known contracts in sorted order.
+func (s *Store) ListContracts() []string {
+	names := make([]string, 0, len(s.addresses))
+	for name := range s.addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Source returns where the contract's address came from: SourceEnv or the
+// path of a deploy output file.
+func (s *Store) Source(name string) string {
+	return s.sources[name]
+}
@@ -0,0 +1,221 @@
+package deployments
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	registrarFromFile = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	registrarFromEnv  = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	helloWorldL2      = common.HexToAddress("0x3000000000000000000000000000000000000003")
+)
+
+type fakeSource map[string]common.Address
+
+func (f fakeSource) GetContract(name string) (common.Address, error) {
+	address, ok := f[name]
+	if !ok {
+		return common.Address{}, fmt.Errorf("contract %s not found", name)
+	}
+	return address, nil
+}
+
+func (f fakeSource) ListContracts() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeOutputs creates dir/devnet/output with the given files.
+func writeOutputs(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	out := filepath.Join(dir, "devnet", "output")
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(out, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func devnetOutputs(t *testing.T) string {
+	return writeOutputs(t, map[string]string{
+		"deploy_avs_l1_output.json":              fmt.Sprintf(`{"addresses":{"taskAVSRegistrar":"%s"},"chainInfo":{"chainId":31337}}`, registrarFromFile),
+		"deploy_custom_contracts_l2_output.json": fmt.Sprintf(`{"addresses":{"helloWorldL2":"%s"},"chainInfo":{"chainId":31338}}`, helloWorldL2),
+		"deploy_custom_contracts_l1_output.json": `{"addresses":{"":""},"chainInfo":{"chainId":0}}`,
+	})
+}
+
+func Test_EnvName(t *testing.T) {
+	for name, want := range map[string]string{
+		"taskAVSRegistrar": "TASK_AVS_REGISTRAR",
+		"helloWorldL2":     "HELLO_WORLD_L2",
+		"taskMailbox":      "TASK_MAILBOX",
+		"HelloWorldL1":     "HELLO_WORLD_L1",
+	} {
+		if got := EnvName(name); got != want {
+			t.Errorf("EnvName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func Test_LoadFromOutputs(t *testing.T) {
+	dir := devnetOutputs(t)
+	store, err := Load(Config{Dir: dir, Environment: "devnet"}, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := []string{"HELLO_WORLD_L2", "TASK_AVS_REGISTRAR"}
+	if got := store.ListContracts(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("ListContracts() = %v, want %v", got, want)
+	}
+	if got, _ := store.GetTaskAVSRegistrar(); got != registrarFromFile {
+		t.Errorf("TASK_AVS_REGISTRAR = %s, want %s", got, registrarFromFile)
+	}
+	if got := store.Source("HELLO_WORLD_L2"); !strings.HasSuffix(got, "deploy_custom_contracts_l2_output.json") {
+		t.Errorf("Source(HELLO_WORLD_L2) = %q", got)
+	}
+}
+
+func Test_LoadPrecedence(t *testing.T) {
+	dir := devnetOutputs(t)
+	env := fakeSource{"TASK_AVS_REGISTRAR": registrarFromEnv}
+
+	tests := []struct {
+		precedence Precedence
+		want       common.Address
+		source     string
+	}{
+		{PreferEnv, registrarFromEnv, SourceEnv},
+		{PreferFiles, registrarFromFile, filepath.Join(dir, "devnet", "output", "deploy_avs_l1_output.json")},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.precedence), func(t *testing.T) {
+			store, err := Load(Config{Dir: dir, Environment: "devnet", Precedence: tt.precedence}, env)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if got, _ := store.GetTaskAVSRegistrar(); got != tt.want {
+				t.Errorf("TASK_AVS_REGISTRAR = %s, want %s", got, tt.want)
+			}
+			if got := store.Source("TASK_AVS_REGISTRAR"); got != tt.source {
+				t.Errorf("Source = %q, want %q", got, tt.source)
+			}
+			// Contracts only in the files are available either way.
+			if _, err := store.GetContract("HELLO_WORLD_L2"); err != nil {
+				t.Errorf("HELLO_WORLD_L2: %v", err)
+			}
+		})
+	}
+}
+
+func Test_LoadWithoutEnvironment(t *testing.T) {
+	env := fakeSource{"TASK_AVS_REGISTRAR": registrarFromEnv}
+	store, err := Load(Config{Dir: "does-not-exist"}, env)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := store.ListContracts(); len(got) != 1 || got[0] != "TASK_AVS_REGISTRAR" {
+		t.Errorf("ListContracts() = %v", got)
+	}
+}
+
+func Test_LoadCheckedInDevnetOutputs(t *testing.T) {
+	// A fresh checkout has placeholder outputs until devkit deploys, and
+	// --contracts-env devnet must still load them
+	env := fakeSource{"TASK_AVS_REGISTRAR": registrarFromEnv}
+	store, err := Load(Config{Dir: filepath.Join("..", "..", DefaultDir), Environment: "devnet"}, env)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got, err := store.GetContract("TASK_AVS_REGISTRAR"); err != nil || got != registrarFromEnv {
+		t.Errorf("TASK_AVS_REGISTRAR = %s, %v; want the env address", got, err)
+	}
+}
+
+func Test_LoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "missing environment",
+			cfg:     Config{Environment: "testnet"},
+			wantErr: "no deploy outputs",
+		},
+		{
+			name:    "invalid precedence",
+			cfg:     Config{Environment: "devnet", Precedence: "newest"},
+			wantErr: "invalid contract precedence",
+		},
+		{
+			name:    "malformed file",
+			files:   map[string]string{"deploy_avs_l1_output.json": `{"addresses":{`},
+			cfg:     Config{Environment: "devnet"},
+			wantErr: "failed to parse",
+		},
+		{
+			name:    "invalid address",
+			files:   map[string]string{"deploy_avs_l1_output.json": `{"addresses":{"taskAVSRegistrar":"0x1234"}}`},
+			cfg:     Config{Environment: "devnet"},
+			wantErr: "invalid address",
+		},
+		{
+			name: "conflicting addresses",
+			files: map[string]string{
+				"a_output.json": fmt.Sprintf(`{"addresses":{"taskMailbox":"%s"}}`, registrarFromFile),
+				"b_output.json": fmt.Sprintf(`{"addresses":{"taskMailbox":"%s"}}`, registrarFromEnv),
+			},
+			cfg:     Config{Environment: "devnet"},
+			wantErr: "TASK_MAILBOX is",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Dir = writeOutputs(t, tt.files)
+			_, err := Load(tt.cfg, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_ConfigFromEnv(t *testing.T) {
+	t.Setenv("PERFORMER_CONTRACTS_ENV", "devnet")
+	t.Setenv("PERFORMER_CONTRACTS_DIR", "")
+	t.Setenv("PERFORMER_CONTRACTS_PRECEDENCE", "files")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	if cfg.Dir != DefaultDir || cfg.Environment != "devnet" || cfg.Precedence != PreferFiles {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	if got, want := cfg.OutputDir(), filepath.Join(DefaultDir, "devnet", "output"); got != want {
+		t.Errorf("OutputDir() = %q, want %q", got, want)
+	}
+
+	t.Setenv("PERFORMER_CONTRACTS_PRECEDENCE", "newest")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Errorf("expected an error for an invalid precedence")
+	}
+}
@@ -0,0 +1,169 @@
+package divergence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultChunkSize is the number of blocks read per eth_getLogs call.
+const DefaultChunkSize = 2000
+
+// EventFilterer reads TaskVerified events. It is implemented by
+// *mailbox.TaskMailbox.
+type EventFilterer interface {
+	FilterTaskVerified(ctx context.Context, from uint64, to uint64, avs common.Address) ([]mailbox.TaskVerifiedEvent, error)
+}
+
+// HeadReader reads the latest block number. It is implemented by
+// *ethclient.Client.
+type HeadReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Divergence is a task whose certified result differs from ours.
+type Divergence struct {
+	TaskHash            common.Hash    `json:"taskHash"`
+	ResultHash          common.Hash    `json:"resultHash"`
+	CertifiedResultHash common.Hash    `json:"certifiedResultHash"`
+	Aggregator          common.Address `json:"aggregator"`
+	Block               uint64         `json:"block"`
+	TxHash              common.Hash    `json:"txHash"`
+}
+
+// Alarm watches TaskVerified events of an AVS and compares the certified
+// results with the ones in the journal.
+type Alarm struct {
+	Journal *Journal
+	Events  EventFilterer
+	Heads   HeadReader
+	Avs     common.Address
+
+	// OnDivergence is called for every task whose certified result differs
+	// from ours.
+	OnDivergence func(Divergence)
+
+	// Webhook, if set, receives every Divergence as a JSON POST.
+	Webhook string
+	Client  *http.Client
+
+	// ChunkSize is the number of blocks per event query; zero uses
+	// DefaultChunkSize.
+	ChunkSize uint64
+}
+
+// Poll checks the TaskVerified events emitted since the journal's checkpoint.
+// The first poll of a new journal starts at the latest block.
+func (a *Alarm) Poll(ctx context.Context) error {
+	head, err := a.Heads.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the latest L2 block: %w", err)
+	}
+	checkpoint := a.Journal.Checkpoint()
+	if checkpoint == 0 {
+		return a.Journal.SetCheckpoint(head)
+	}
+	chunk := a.ChunkSize
+	if chunk == 0 {
+		chunk = DefaultChunkSize
+	}
+
+	// Webhook failures don't hold back the checkpoint, so that a broken
+	// webhook doesn't report the same divergence again on every poll.
+	var webhookErrs []error
+	for from := checkpoint + 1; from <= head; from += chunk {
+		to := min(from+chunk-1, head)
+		events, err := a.Events.FilterTaskVerified(ctx, from, to, a.Avs)
+		if err != nil {
+			return errors.Join(append(webhookErrs, err)...)
+		}
+		for _, ev := range events {
+			d, err := a.check(ev)
+			if err != nil {
+				return errors.Join(append(webhookErrs, err)...)
+			}
+			if d == nil {
+				continue
+			}
+			if a.OnDivergence != nil {
+				a.OnDivergence(*d)
+			}
+			if a.Webhook != "" {
+				if err := a.post(ctx, *d); err != nil {
+					webhookErrs = append(webhookErrs, fmt.Errorf("failed to send divergence of task %s to the webhook: %w", d.TaskHash, err))
+				}
+			}
+		}
+		if err := a.Journal.SetCheckpoint(to); err != nil {
+			return errors.Join(append(webhookErrs, err)...)
+		}
+	}
+	return errors.Join(webhookErrs...)
+}
+
+// check records the certified result of ev in the journal and returns the
+// divergence, if our result differs.
+func (a *Alarm) check(ev mailbox.TaskVerifiedEvent) (*Divergence, error) {
+	entry, ok, err := a.Journal.Verify(ev.TaskHash, ev.Result, ev.Raw.BlockNumber)
+	if err != nil || !ok || !entry.Diverged {
+		return nil, err
+	}
+	return &Divergence{
+		TaskHash:            ev.TaskHash,
+		ResultHash:          entry.ResultHash,
+		CertifiedResultHash: *entry.CertifiedResultHash,
+		Aggregator:          ev.Aggregator,
+		Block:               ev.Raw.BlockNumber,
+		TxHash:              ev.Raw.TxHash,
+	}, nil
+}
+
+func (a *Alarm) post(ctx context.Context, d Divergence) error {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := a.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Watch polls every interval until ctx is done. Errors are reported to
+// onError; blocks that could not be checked are retried on the next poll.
+func (a *Alarm) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := a.Poll(ctx); err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package divergence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var avs = common.HexToAddress("0xa5")
+
+func openJournal(t *testing.T, path string, size int) *Journal {
+	t.Helper()
+	j, err := OpenJournal(path, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = j.Close() })
+	return j
+}
+
+func Test_Journal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j := openJournal(t, path, 3)
+	now := time.Unix(1_700_000_000, 0)
+
+	for i := byte(1); i <= 5; i++ {
+		if err := j.Record(common.Hash{i}, []byte{i}, now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok, _ := j.Verify(common.Hash{1}, []byte{1}, 10); ok {
+		t.Error("the oldest task should have been dropped")
+	}
+	entry, ok, err := j.Verify(common.Hash{4}, []byte{4}, 10)
+	if err != nil || !ok || entry.Diverged {
+		t.Errorf("expected a matching result, got %+v, %t, %v", entry, ok, err)
+	}
+	entry, ok, err = j.Verify(common.Hash{5}, []byte("other"), 11)
+	if err != nil || !ok || !entry.Diverged || entry.VerifiedBlock != 11 {
+		t.Errorf("expected a divergence, got %+v, %t, %v", entry, ok, err)
+	}
+	if err := j.SetCheckpoint(11); err != nil {
+		t.Fatal(err)
+	}
+
+	// Compaction keeps the state and reopening restores it
+	for i := 0; i < 10; i++ {
+		if err := j.SetCheckpoint(12); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := read.Last(10)
+	if len(last) != 3 || last[0].TaskHash != (common.Hash{5}) || !last[0].Diverged || read.Checkpoint() != 12 {
+		t.Errorf("unexpected journal after reopening: %+v, checkpoint %d", last, read.Checkpoint())
+	}
+	if s := Summarize(last); s != (Summary{Tasks: 3, Matched: 1, Diverged: 1, Pending: 1}) {
+		t.Errorf("unexpected summary %+v", s)
+	}
+	if _, err := ReadJournal(filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("expected an error for a missing journal")
+	}
+}
+
+type fakeEvents []mailbox.TaskVerifiedEvent
+
+func (f fakeEvents) FilterTaskVerified(_ context.Context, from uint64, to uint64, _ common.Address) ([]mailbox.TaskVerifiedEvent, error) {
+	var out []mailbox.TaskVerifiedEvent
+	for _, ev := range f {
+		if ev.Raw.BlockNumber >= from && ev.Raw.BlockNumber <= to {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+type fixedHead uint64
+
+func (h fixedHead) BlockNumber(context.Context) (uint64, error) { return uint64(h), nil }
+
+func Test_Alarm(t *testing.T) {
+	j := openJournal(t, filepath.Join(t.TempDir(), "journal.jsonl"), 0)
+	if err := j.Record(common.Hash{1}, []byte("ours"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Record(common.Hash{2}, []byte("same"), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	posted := make(chan Divergence, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var d Divergence
+		if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+			t.Errorf("invalid webhook body: %v", err)
+		}
+		posted <- d
+	}))
+	defer server.Close()
+
+	var alarms []Divergence
+	alarm := &Alarm{
+		Journal: j,
+		Events: fakeEvents{
+			{TaskHash: common.Hash{1}, Result: []byte("theirs"), Raw: types.Log{BlockNumber: 105}},
+			{TaskHash: common.Hash{2}, Result: []byte("same"), Raw: types.Log{BlockNumber: 106}},
+			{TaskHash: common.Hash{3}, Result: []byte("not ours"), Raw: types.Log{BlockNumber: 107}},
+		},
+		Heads:        fixedHead(100),
+		Avs:          avs,
+		OnDivergence: func(d Divergence) { alarms = append(alarms, d) },
+		Webhook:      server.URL,
+		ChunkSize:    3,
+	}
+
+	// The first poll only sets the checkpoint
+	if err := alarm.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if j.Checkpoint() != 100 {
+		t.Fatalf("checkpoint = %d, want 100", j.Checkpoint())
+	}
+
+	alarm.Heads = fixedHead(110)
+	if err := alarm.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(alarms) != 1 || alarms[0].TaskHash != (common.Hash{1}) || alarms[0].CertifiedResultHash != crypto.Keccak256Hash([]byte("theirs")) {
+		t.Errorf("unexpected divergences %+v", alarms)
+	}
+	if d := <-posted; d.TaskHash != (common.Hash{1}) || d.Block != 105 {
+		t.Errorf("unexpected webhook divergence %+v", d)
+	}
+	if j.Checkpoint() != 110 {
+		t.Errorf("checkpoint = %d, want 110", j.Checkpoint())
+	}
+
+	// Polling again doesn't repeat the alarm
+	if err := alarm.Poll(context.Background()); err != nil || len(alarms) != 1 {
+		t.Errorf("unexpected second poll: %v, %+v", err, alarms)
+	}
+}
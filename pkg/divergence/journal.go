@@ -0,0 +1,295 @@
+// Package divergence keeps a journal of the results the Performer produced
+// and raises an alarm when the result certified by a TaskVerified event on L2
+// differs from ours. Every disagreement means our operator signed a result
+// the other operators did not, which points at a nondeterminism bug and can be
+// slashable.
+package divergence
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultJournalSize is the number of tasks a journal keeps by default.
+const DefaultJournalSize = 10000
+
+// Entry is a task the Performer handled.
+type Entry struct {
+	TaskHash   common.Hash `json:"taskHash"`
+	ResultHash common.Hash `json:"resultHash"`
+	HandledAt  time.Time   `json:"handledAt"`
+
+	// The fields below are set once the task's TaskVerified event is seen.
+	Verified            bool         `json:"verified,omitempty"`
+	CertifiedResultHash *common.Hash `json:"certifiedResultHash,omitempty"`
+	VerifiedBlock       uint64       `json:"verifiedBlock,omitempty"`
+	Diverged            bool         `json:"diverged,omitempty"`
+}
+
+// record is one line of the journal file. Later entries for a task replace
+// earlier ones.
+type record struct {
+	Entry      *Entry  `json:"entry,omitempty"`
+	Checkpoint *uint64 `json:"checkpoint,omitempty"`
+}
+
+// Journal is an append-only file of the tasks the Performer handled and the
+// last L2 block checked for TaskVerified events. It keeps the most recent
+// tasks, up to its size.
+type Journal struct {
+	path string
+	size int
+
+	mu         sync.Mutex
+	file       *os.File
+	entries    map[common.Hash]*Entry
+	order      []common.Hash
+	checkpoint uint64
+	lines      int
+}
+
+// OpenJournal opens the journal at path, creating it if needed. size bounds
+// the number of tasks kept; zero uses DefaultJournalSize.
+func OpenJournal(path string, size int) (*Journal, error) {
+	if size <= 0 {
+		size = DefaultJournalSize
+	}
+	j := &Journal{path: path, size: size, entries: make(map[common.Hash]*Entry)}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	j.file = f
+	return j, nil
+}
+
+// ReadJournal reads the journal at path without opening it for writing.
+func ReadJournal(path string) (*Journal, error) {
+	j := &Journal{path: path, size: DefaultJournalSize, entries: make(map[common.Hash]*Entry)}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	if j.lines == 0 {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	}
+	return j, nil
+}
+
+func (j *Journal) load() error {
+	f, err := os.Open(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// A crash can leave a partial last line; skip it
+			continue
+		}
+		j.apply(r)
+		j.lines++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read journal %s: %w", j.path, err)
+	}
+	return nil
+}
+
+// apply adds r to the in-memory state.
+func (j *Journal) apply(r record) {
+	if r.Checkpoint != nil && *r.Checkpoint > j.checkpoint {
+		j.checkpoint = *r.Checkpoint
+	}
+	if r.Entry == nil {
+		return
+	}
+	e := *r.Entry
+	if _, ok := j.entries[e.TaskHash]; !ok {
+		j.order = append(j.order, e.TaskHash)
+	}
+	j.entries[e.TaskHash] = &e
+	for len(j.order) > j.size {
+		delete(j.entries, j.order[0])
+		j.order = j.order[1:]
+	}
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// Record adds the result the Performer produced for a task.
+func (j *Journal) Record(taskHash common.Hash, result []byte, now time.Time) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.write(record{Entry: &Entry{TaskHash: taskHash, ResultHash: crypto.Keccak256Hash(result), HandledAt: now}})
+}
+
+// Verify records the certified result of a task and reports whether it
+// differs from ours. ok is false if the task is not in the journal, i.e. the
+// Performer did not handle it or it is too old.
+func (j *Journal) Verify(taskHash common.Hash, certified []byte, block uint64) (entry Entry, ok bool, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e, ok := j.entries[taskHash]
+	if !ok {
+		return Entry{}, false, nil
+	}
+	updated := *e
+	certifiedHash := crypto.Keccak256Hash(certified)
+	updated.Verified = true
+	updated.CertifiedResultHash = &certifiedHash
+	updated.VerifiedBlock = block
+	updated.Diverged = certifiedHash != e.ResultHash
+	return updated, true, j.write(record{Entry: &updated})
+}
+
+// Checkpoint returns the last L2 block checked for TaskVerified events, or
+// zero if none was.
+func (j *Journal) Checkpoint() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.checkpoint
+}
+
+// SetCheckpoint records that every block up to block was checked.
+func (j *Journal) SetCheckpoint(block uint64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.write(record{Checkpoint: &block})
+}
+
+// Last returns up to n of the most recently handled tasks, newest first.
+func (j *Journal) Last(n int) []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if n <= 0 || n > len(j.order) {
+		n = len(j.order)
+	}
+	out := make([]Entry, 0, n)
+	for i := len(j.order) - 1; i >= 0 && len(out) < n; i-- {
+		out = append(out, *j.entries[j.order[i]])
+	}
+	return out
+}
+
+// write appends r to the file and applies it. The file is compacted once it
+// holds twice as many lines as the journal keeps.
+func (j *Journal) write(r record) error {
+	if j.file == nil {
+		return errors.New("journal is not open for writing")
+	}
+	j.apply(r)
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	j.lines++
+	if j.lines > 2*j.size {
+		return j.compact()
+	}
+	return nil
+}
+
+// compact rewrites the file with only the entries and checkpoint in memory.
+func (j *Journal) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	checkpoint := j.checkpoint
+	if err := enc.Encode(record{Checkpoint: &checkpoint}); err != nil {
+		tmp.Close()
+		return err
+	}
+	for _, hash := range j.order {
+		if err := enc.Encode(record{Entry: j.entries[hash]}); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_ = j.file.Close()
+	j.file = f
+	j.lines = len(j.order) + 1
+	return nil
+}
+
+// Summary counts the outcomes of journal entries.
+type Summary struct {
+	Tasks    int `json:"tasks"`
+	Matched  int `json:"matched"`
+	Diverged int `json:"diverged"`
+	Pending  int `json:"pending"`
+}
+
+// Summarize counts how many of entries matched, diverged from or are still
+// waiting for their certified result.
+func Summarize(entries []Entry) Summary {
+	s := Summary{Tasks: len(entries)}
+	for _, e := range entries {
+		switch {
+		case !e.Verified:
+			s.Pending++
+		case e.Diverged:
+			s.Diverged++
+		default:
+			s.Matched++
+		}
+	}
+	return s
+}
@@ -0,0 +1,182 @@
+// Package golden loads task fixtures from disk and compares task results
+// against golden files. Each fixture is a directory containing:
+//
+//	payload.hex    task payload as hex (required)
+//	task_id.hex    task ID as hex (optional, defaults to keccak256 of the payload)
+//	result.golden  expected result as hex
+//	error.golden   expected error message
+//
+// A fixture expects either a result or an error. Golden files are rewritten
+// from the actual outcome with Update.
+package golden
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	PayloadFile = "payload.hex"
+	TaskIdFile  = "task_id.hex"
+	ResultFile  = "result.golden"
+	ErrorFile   = "error.golden"
+)
+
+// Case is a single task fixture.
+type Case struct {
+	Name    string
+	Dir     string
+	TaskId  []byte
+	Payload []byte
+
+	// WantResult is set if the fixture has a result.golden file
+	WantResult []byte
+	// WantError is set if the fixture has an error.golden file
+	WantError *string
+}
+
+// LoadCases loads every fixture directory directly below root, sorted by name.
+func LoadCases(root string) ([]*Case, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var cases []*Case
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		c, err := LoadCase(filepath.Join(root, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, nil
+}
+
+// LoadCase loads the fixture in dir.
+func LoadCase(dir string) (*Case, error) {
+	c := &Case{Name: filepath.Base(dir), Dir: dir}
+
+	var err error
+	if c.Payload, err = readHex(filepath.Join(dir, PayloadFile)); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", c.Name, err)
+	}
+
+	c.TaskId, err = readHex(filepath.Join(dir, TaskIdFile))
+	if errors.Is(err, os.ErrNotExist) {
+		c.TaskId = crypto.Keccak256(c.Payload)
+	} else if err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", c.Name, err)
+	}
+
+	c.WantResult, err = readHex(filepath.Join(dir, ResultFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("fixture %s: %w", c.Name, err)
+	}
+
+	wantErr, err := os.ReadFile(filepath.Join(dir, ErrorFile))
+	if err == nil {
+		msg := strings.TrimSpace(string(wantErr))
+		c.WantError = &msg
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("fixture %s: %w", c.Name, err)
+	}
+
+	if c.WantResult != nil && c.WantError != nil {
+		return nil, fmt.Errorf("fixture %s: has both %s and %s", c.Name, ResultFile, ErrorFile)
+	}
+	return c, nil
+}
+
+// Check compares a task outcome with the fixture's golden files.
+func (c *Case) Check(result []byte, taskErr error) error {
+	switch {
+	case c.WantError != nil:
+		if taskErr == nil {
+			return fmt.Errorf("expected error %q, got result %s", *c.WantError, hexutil.Encode(result))
+		}
+		if taskErr.Error() != *c.WantError {
+			return fmt.Errorf("error mismatch\n got: %s\nwant: %s", taskErr, *c.WantError)
+		}
+	case c.WantResult != nil:
+		if taskErr != nil {
+			return fmt.Errorf("expected result %s, got error: %v", hexutil.Encode(c.WantResult), taskErr)
+		}
+		if hexutil.Encode(result) != hexutil.Encode(c.WantResult) {
+			return fmt.Errorf("result mismatch\n got: %s\nwant: %s", hexutil.Encode(result), hexutil.Encode(c.WantResult))
+		}
+	default:
+		return fmt.Errorf("fixture has neither %s nor %s; run with -update to create one", ResultFile, ErrorFile)
+	}
+	return nil
+}
+
+// Update rewrites the fixture's golden files from a task outcome.
+func (c *Case) Update(result []byte, taskErr error) error {
+	resultPath := filepath.Join(c.Dir, ResultFile)
+	errorPath := filepath.Join(c.Dir, ErrorFile)
+
+	if taskErr != nil {
+		if err := os.WriteFile(errorPath, []byte(taskErr.Error()+"\n"), 0o644); err != nil {
+			return err
+		}
+		msg := taskErr.Error()
+		c.WantError, c.WantResult = &msg, nil
+		return removeIfExists(resultPath)
+	}
+
+	if err := os.WriteFile(resultPath, []byte(hexutil.Encode(result)+"\n"), 0o644); err != nil {
+		return err
+	}
+	c.WantResult, c.WantError = append([]byte{}, result...), nil
+	return removeIfExists(errorPath)
+}
+
+// WriteCase creates a fixture directory for a payload, e.g. to export tasks
+// seen on chain for offline tests.
+func WriteCase(dir string, taskId []byte, payload []byte) (*Case, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, PayloadFile), []byte(hexutil.Encode(payload)+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, TaskIdFile), []byte(hexutil.Encode(taskId)+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+	return &Case{Name: filepath.Base(dir), Dir: dir, TaskId: taskId, Payload: payload}, nil
+}
+
+func readHex(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := strings.TrimSpace(string(raw))
+	if !strings.HasPrefix(s, "0x") {
+		s = "0x" + s
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return b, nil
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
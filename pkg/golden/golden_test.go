@@ -0,0 +1,60 @@
+package golden
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_GoldenCases(t *testing.T) {
+	root := t.TempDir()
+
+	ok, err := WriteCase(filepath.Join(root, "b-ok"), []byte{0x01}, []byte("payload"))
+	if err != nil {
+		t.Fatalf("WriteCase failed: %v", err)
+	}
+	if err := ok.Update([]byte{0xbe, 0xef}, nil); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	bad, err := WriteCase(filepath.Join(root, "a-bad"), []byte{0x02}, []byte{})
+	if err != nil {
+		t.Fatalf("WriteCase failed: %v", err)
+	}
+	if err := bad.Update(nil, errors.New("payload is empty")); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	cases, err := LoadCases(root)
+	if err != nil {
+		t.Fatalf("LoadCases failed: %v", err)
+	}
+	if len(cases) != 2 || cases[0].Name != "a-bad" || cases[1].Name != "b-ok" {
+		t.Fatalf("unexpected cases: %+v", cases)
+	}
+
+	if err := cases[1].Check([]byte{0xbe, 0xef}, nil); err != nil {
+		t.Errorf("expected matching result to pass: %v", err)
+	}
+	if err := cases[1].Check([]byte{0x00}, nil); err == nil {
+		t.Error("expected different result to fail")
+	}
+	if err := cases[1].Check(nil, errors.New("boom")); err == nil {
+		t.Error("expected unexpected error to fail")
+	}
+	if err := cases[0].Check(nil, errors.New("payload is empty")); err != nil {
+		t.Errorf("expected matching error to pass: %v", err)
+	}
+	if err := cases[0].Check([]byte{}, nil); err == nil {
+		t.Error("expected missing error to fail")
+	}
+
+	// Updating a case from an error to a result removes the stale error file
+	if err := cases[0].Update([]byte{0x01}, nil); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cases[0].Dir, ErrorFile)); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed", ErrorFile)
+	}
+}
@@ -0,0 +1,115 @@
+package limiter
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the limits read from the Performer environment.
+type Config struct {
+	Defaults Limits
+	Handlers map[string]Limits
+	L1RPC    RPCRate
+	L2RPC    RPCRate
+}
+
+// ConfigFromEnv reads limits from the environment:
+//
+//	PERFORMER_MAX_CONCURRENT_TASKS  concurrent tasks per handler (0 = unlimited)
+//	PERFORMER_MAX_QUEUED_TASKS      tasks waiting for a slot per handler
+//	PERFORMER_QUEUE_TIMEOUT         max time a task waits in the queue, e.g. "5s"
+//	PERFORMER_HANDLER_LIMITS        per-handler overrides, e.g. "default=4:16,heavy=1:2"
+//	L1_RPC_RATE_LIMIT, L1_RPC_BURST requests/second and burst for L1_RPC_URL
+//	L2_RPC_RATE_LIMIT, L2_RPC_BURST requests/second and burst for L2_RPC_URL
+func ConfigFromEnv() (*Config, error) {
+	cfg := &Config{Handlers: make(map[string]Limits)}
+
+	var err error
+	if cfg.Defaults.MaxConcurrent, err = envInt("PERFORMER_MAX_CONCURRENT_TASKS"); err != nil {
+		return nil, err
+	}
+	if cfg.Defaults.MaxQueued, err = envInt("PERFORMER_MAX_QUEUED_TASKS"); err != nil {
+		return nil, err
+	}
+	if v := os.Getenv("PERFORMER_QUEUE_TIMEOUT"); v != "" {
+		if cfg.Defaults.QueueTimeout, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid PERFORMER_QUEUE_TIMEOUT: %w", err)
+		}
+	}
+
+	if v := os.Getenv("PERFORMER_HANDLER_LIMITS"); v != "" {
+		if cfg.Handlers, err = ParseHandlerLimits(v, cfg.Defaults); err != nil {
+			return nil, fmt.Errorf("invalid PERFORMER_HANDLER_LIMITS: %w", err)
+		}
+	}
+
+	if cfg.L1RPC, err = rpcRateFromEnv("L1"); err != nil {
+		return nil, err
+	}
+	if cfg.L2RPC, err = rpcRateFromEnv("L2"); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ParseHandlerLimits parses a comma separated list of name=concurrent:queued
+// entries. The queue timeout is inherited from defaults.
+func ParseHandlerLimits(s string, defaults Limits) (map[string]Limits, error) {
+	out := make(map[string]Limits)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=concurrent:queued, got %q", entry)
+		}
+		concurrent, queued, _ := strings.Cut(spec, ":")
+
+		limits := defaults
+		var err error
+		if limits.MaxConcurrent, err = strconv.Atoi(concurrent); err != nil {
+			return nil, fmt.Errorf("invalid concurrency for handler %q: %w", name, err)
+		}
+		if queued != "" {
+			if limits.MaxQueued, err = strconv.Atoi(queued); err != nil {
+				return nil, fmt.Errorf("invalid queue size for handler %q: %w", name, err)
+			}
+		}
+		out[name] = limits
+	}
+	return out, nil
+}
+
+func rpcRateFromEnv(chain string) (RPCRate, error) {
+	var rate RPCRate
+	if v := os.Getenv(chain + "_RPC_RATE_LIMIT"); v != "" {
+		rps, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return rate, fmt.Errorf("invalid %s_RPC_RATE_LIMIT: %w", chain, err)
+		}
+		rate.RequestsPerSecond = rps
+	}
+	burst, err := envInt(chain + "_RPC_BURST")
+	if err != nil {
+		return rate, err
+	}
+	rate.Burst = burst
+	return rate, nil
+}
+
+func envInt(name string) (int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,176 @@
+// Package limiter provides admission control for the Performer: bounded
+// per-handler concurrency with a bounded wait queue, and per-endpoint rate
+// limiting for RPC traffic.
+package limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrResourceExhausted is matched (via errors.Is) by every error returned when a
+// task is rejected because the Performer is at capacity.
+var ErrResourceExhausted = errors.New("resource exhausted")
+
+// ExhaustedError is returned when a task cannot be admitted. It carries the
+// gRPC ResourceExhausted code so the Executor can tell it apart from a task
+// failure.
+type ExhaustedError struct {
+	Handler string
+	Reason  string
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("%s: handler %q: %s", ErrResourceExhausted, e.Handler, e.Reason)
+}
+
+func (e *ExhaustedError) Is(target error) bool {
+	return target == ErrResourceExhausted
+}
+
+// GRPCStatus allows the gRPC server to map this error to codes.ResourceExhausted.
+func (e *ExhaustedError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
+// Limits bounds the number of tasks a single handler may run at once.
+type Limits struct {
+	// MaxConcurrent is the number of tasks that may execute concurrently.
+	// Zero disables the limit.
+	MaxConcurrent int
+
+	// MaxQueued is the number of tasks that may wait for a free slot. When the
+	// queue is full new tasks are rejected immediately.
+	MaxQueued int
+
+	// QueueTimeout bounds how long a queued task waits for a slot. Zero waits
+	// until the caller's context is done.
+	QueueTimeout time.Duration
+}
+
+// TaskLimiter hands out execution slots per handler.
+type TaskLimiter struct {
+	defaults  Limits
+	overrides map[string]Limits
+
+	mu       sync.Mutex
+	handlers map[string]*handlerSlots
+}
+
+type handlerSlots struct {
+	limits Limits
+	slots  chan struct{}
+
+	mu     sync.Mutex
+	queued int
+}
+
+// NewTaskLimiter creates a limiter that applies defaults to every handler
+// unless an override is given for it.
+func NewTaskLimiter(defaults Limits, overrides map[string]Limits) *TaskLimiter {
+	return &TaskLimiter{
+		defaults:  defaults,
+		overrides: overrides,
+		handlers:  make(map[string]*handlerSlots),
+	}
+}
+
+func (l *TaskLimiter) slotsFor(handler string) *handlerSlots {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if h, ok := l.handlers[handler]; ok {
+		return h
+	}
+
+	limits, ok := l.overrides[handler]
+	if !ok {
+		limits = l.defaults
+	}
+	h := &handlerSlots{limits: limits}
+	if limits.MaxConcurrent > 0 {
+		h.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	l.handlers[handler] = h
+	return h
+}
+
+// SetLimits replaces the limiter's limits. Handlers whose limits change get
+// new slots: tasks already running or queued keep their old slots, so a
+// handler may briefly run more tasks than its new limit allows.
+func (l *TaskLimiter) SetLimits(defaults Limits, overrides map[string]Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.defaults = defaults
+	l.overrides = overrides
+	for name, h := range l.handlers {
+		limits, ok := overrides[name]
+		if !ok {
+			limits = defaults
+		}
+		if limits != h.limits {
+			delete(l.handlers, name)
+		}
+	}
+}
+
+// Acquire reserves an execution slot for handler. The returned release function
+// must be called once the task has finished. If no slot is free and the queue
+// is full, or the queue wait times out, an *ExhaustedError is returned.
+func (l *TaskLimiter) Acquire(ctx context.Context, handler string) (func(), error) {
+	h := l.slotsFor(handler)
+	if h.slots == nil {
+		return func() {}, nil
+	}
+
+	release := func() { <-h.slots }
+
+	// Fast path: a slot is free
+	select {
+	case h.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	h.mu.Lock()
+	if h.queued >= h.limits.MaxQueued {
+		h.mu.Unlock()
+		return nil, &ExhaustedError{Handler: handler, Reason: "queue is full"}
+	}
+	h.queued++
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.queued--
+		h.mu.Unlock()
+	}()
+
+	if h.limits.QueueTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.limits.QueueTimeout)
+		defer cancel()
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, &ExhaustedError{Handler: handler, Reason: "timed out waiting for a free slot"}
+	}
+}
+
+// Stats reports the number of running and queued tasks for handler.
+func (l *TaskLimiter) Stats(handler string) (running int, queued int) {
+	h := l.slotsFor(handler)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.slots), h.queued
+}
@@ -0,0 +1,153 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_TaskLimiter(t *testing.T) {
+	l := NewTaskLimiter(Limits{MaxConcurrent: 1, MaxQueued: 1, QueueTimeout: 50 * time.Millisecond}, nil)
+	ctx := context.Background()
+
+	release, err := l.Acquire(ctx, "default")
+	if err != nil {
+		t.Fatalf("first Acquire failed: %v", err)
+	}
+
+	// One task may wait in the queue; it is admitted once the slot frees up.
+	admitted := make(chan error, 1)
+	go func() {
+		r, err := l.Acquire(ctx, "default")
+		if err == nil {
+			r()
+		}
+		admitted <- err
+	}()
+
+	// Wait for the queued task to be counted
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, queued := l.Stats("default"); queued == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("task was never queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The queue is full, so the next task is rejected straight away.
+	_, err = l.Acquire(ctx, "default")
+	if !errors.Is(err, ErrResourceExhausted) {
+		t.Fatalf("expected ErrResourceExhausted, got %v", err)
+	}
+	if s, _ := status.FromError(err); s.Code() != codes.ResourceExhausted {
+		t.Errorf("expected gRPC code ResourceExhausted, got %v", s.Code())
+	}
+
+	release()
+	if err := <-admitted; err != nil {
+		t.Errorf("queued task was not admitted: %v", err)
+	}
+}
+
+func Test_TaskLimiterQueueTimeout(t *testing.T) {
+	l := NewTaskLimiter(Limits{MaxConcurrent: 1, MaxQueued: 1, QueueTimeout: 10 * time.Millisecond}, nil)
+
+	release, err := l.Acquire(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("first Acquire failed: %v", err)
+	}
+	defer release()
+
+	if _, err := l.Acquire(context.Background(), "default"); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("expected queue timeout to return ErrResourceExhausted, got %v", err)
+	}
+}
+
+func Test_TaskLimiterHandlerOverrides(t *testing.T) {
+	l := NewTaskLimiter(Limits{}, map[string]Limits{"heavy": {MaxConcurrent: 1}})
+
+	// Handlers without an override are unlimited
+	for i := 0; i < 10; i++ {
+		if _, err := l.Acquire(context.Background(), "light"); err != nil {
+			t.Fatalf("unlimited handler was rejected: %v", err)
+		}
+	}
+
+	if _, err := l.Acquire(context.Background(), "heavy"); err != nil {
+		t.Fatalf("first heavy task was rejected: %v", err)
+	}
+	if _, err := l.Acquire(context.Background(), "heavy"); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("expected second heavy task to be rejected, got %v", err)
+	}
+}
+
+func Test_TaskLimiterSetLimits(t *testing.T) {
+	l := NewTaskLimiter(Limits{MaxConcurrent: 1}, nil)
+	ctx := context.Background()
+
+	if _, err := l.Acquire(ctx, "default"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Acquire(ctx, "default"); !errors.Is(err, ErrResourceExhausted) {
+		t.Fatalf("expected the second task to be rejected, got %v", err)
+	}
+
+	// Raising the limit takes effect for the next task
+	l.SetLimits(Limits{MaxConcurrent: 1}, map[string]Limits{"default": {MaxConcurrent: 2}})
+	for i := 0; i < 2; i++ {
+		if _, err := l.Acquire(ctx, "default"); err != nil {
+			t.Fatalf("task %d was rejected after raising the limit: %v", i, err)
+		}
+	}
+	if running, _ := l.Stats("default"); running != 2 {
+		t.Errorf("running = %d, want 2", running)
+	}
+}
+
+func Test_ParseHandlerLimits(t *testing.T) {
+	got, err := ParseHandlerLimits("default=4:16, heavy=1", Limits{MaxQueued: 2, QueueTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("ParseHandlerLimits failed: %v", err)
+	}
+	if got["default"] != (Limits{MaxConcurrent: 4, MaxQueued: 16, QueueTimeout: time.Second}) {
+		t.Errorf("unexpected default limits: %+v", got["default"])
+	}
+	if got["heavy"] != (Limits{MaxConcurrent: 1, MaxQueued: 2, QueueTimeout: time.Second}) {
+		t.Errorf("unexpected heavy limits: %+v", got["heavy"])
+	}
+
+	if _, err := ParseHandlerLimits("broken", Limits{}); err == nil {
+		t.Error("expected an error for a malformed entry")
+	}
+}
+
+func Test_RateLimiter(t *testing.T) {
+	r := NewRateLimiter(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := r.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	// Two requests come from the burst, the other two wait ~10ms each.
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("expected requests to be throttled, took %v", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	slow := NewRateLimiter(0.001, 1)
+	_ = slow.Wait(ctx)
+	if err := slow.Wait(cancelled); err == nil {
+		t.Error("expected Wait to return when the context is cancelled")
+	}
+}
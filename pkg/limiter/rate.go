@@ -0,0 +1,137 @@
+package limiter
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RateLimiter is a token bucket allowing rate requests per second with bursts
+// of up to burst requests.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a token bucket that starts full.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := r.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token if one is available and otherwise returns how long
+// the caller should wait before trying again.
+func (r *RateLimiter) reserve() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+	return time.Duration((1 - r.tokens) / r.rate * float64(time.Second))
+}
+
+// RateLimitedTransport is an http.RoundTripper that waits on a RateLimiter
+// before every request.
+type RateLimitedTransport struct {
+	Limiter *RateLimiter
+	Next    http.RoundTripper
+}
+
+func (t *RateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
+
+// RPCRate configures the rate limit for a single RPC endpoint. A zero
+// RequestsPerSecond disables limiting.
+type RPCRate struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+var (
+	endpointMu       sync.Mutex
+	endpointLimiters = make(map[string]*RateLimiter)
+)
+
+// EndpointLimiter returns the RateLimiter shared by every client of url,
+// creating it with rate on first use.
+func EndpointLimiter(url string, rate RPCRate) *RateLimiter {
+	endpointMu.Lock()
+	defer endpointMu.Unlock()
+
+	if l, ok := endpointLimiters[url]; ok {
+		return l
+	}
+	l := NewRateLimiter(rate.RequestsPerSecond, rate.Burst)
+	endpointLimiters[url] = l
+	return l
+}
+
+// DialRateLimited connects to an RPC endpoint. For HTTP(S) endpoints every
+// contract binding built on the returned client shares the endpoint's rate
+// limit. next, if not nil, carries the HTTP traffic after the rate limiter.
+func DialRateLimited(ctx context.Context, url string, rate RPCRate, next http.RoundTripper) (*ethclient.Client, error) {
+	if rate.RequestsPerSecond <= 0 && next == nil {
+		return ethclient.DialContext(ctx, url)
+	}
+
+	transport := next
+	if rate.RequestsPerSecond > 0 {
+		transport = &RateLimitedTransport{Limiter: EndpointLimiter(url, rate), Next: next}
+	}
+	httpClient := &http.Client{Transport: transport}
+	c, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(c), nil
+}
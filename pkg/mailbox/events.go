@@ -0,0 +1,90 @@
+package mailbox
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TaskCreatedEvent is the TaskMailbox's TaskCreated event.
+type TaskCreatedEvent struct {
+	Creator                         common.Address
+	TaskHash                        common.Hash
+	Avs                             common.Address
+	ExecutorOperatorSetId           uint32
+	OperatorTableReferenceTimestamp uint32
+	RefundCollector                 common.Address
+	AvsFee                          *big.Int
+	TaskDeadline                    *big.Int
+	Payload                         []byte
+	Raw                             types.Log
+}
+
+// TaskVerifiedEvent is the TaskMailbox's TaskVerified event.
+type TaskVerifiedEvent struct {
+	Aggregator            common.Address
+	TaskHash              common.Hash
+	Avs                   common.Address
+	ExecutorOperatorSetId uint32
+	ExecutorCert          []byte
+	Result                []byte
+	Raw                   types.Log
+}
+
+// FilterTaskCreated returns the TaskCreated events of avs emitted between
+// blocks from and to, inclusive.
+func (m *TaskMailbox) FilterTaskCreated(ctx context.Context, from uint64, to uint64, avs common.Address) ([]TaskCreatedEvent, error) {
+	logs, err := m.filterLogs(ctx, "TaskCreated", from, to, avs)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]TaskCreatedEvent, 0, len(logs))
+	for _, log := range logs {
+		var ev TaskCreatedEvent
+		if err := m.contract.UnpackLog(&ev, "TaskCreated", log); err != nil {
+			return nil, fmt.Errorf("failed to decode TaskCreated in %s: %w", log.TxHash, err)
+		}
+		ev.Raw = log
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// FilterTaskVerified returns the TaskVerified events of avs emitted between
+// blocks from and to, inclusive.
+func (m *TaskMailbox) FilterTaskVerified(ctx context.Context, from uint64, to uint64, avs common.Address) ([]TaskVerifiedEvent, error) {
+	logs, err := m.filterLogs(ctx, "TaskVerified", from, to, avs)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]TaskVerifiedEvent, 0, len(logs))
+	for _, log := range logs {
+		var ev TaskVerifiedEvent
+		if err := m.contract.UnpackLog(&ev, "TaskVerified", log); err != nil {
+			return nil, fmt.Errorf("failed to decode TaskVerified in %s: %w", log.TxHash, err)
+		}
+		ev.Raw = log
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// filterLogs returns the logs of event whose avs topic, the third indexed
+// argument of both task events, is avs.
+func (m *TaskMailbox) filterLogs(ctx context.Context, event string, from uint64, to uint64, avs common.Address) ([]types.Log, error) {
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{m.address},
+		Topics:    [][]common.Hash{{m.abi.Events[event].ID}, nil, nil, {common.BytesToHash(avs.Bytes())}},
+	}
+	logs, err := m.filterer.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s events: %w", event, err)
+	}
+	return logs, nil
+}
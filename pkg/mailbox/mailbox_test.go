@@ -0,0 +1,477 @@
+package mailbox
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/reverts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	avs      = common.HexToAddress("0xa000000000000000000000000000000000000001")
+	otherAvs = common.HexToAddress("0xb000000000000000000000000000000000000002")
+	payload  = []byte("hello world")
+	taskHash = crypto.Keccak256Hash([]byte("task"))
+)
+
+func createdTask() *Task {
+	return &Task{
+		Creator:               common.HexToAddress("0xc000000000000000000000000000000000000003"),
+		CreationTime:          big.NewInt(1_700_000_000),
+		Avs:                   avs,
+		AvsFee:                big.NewInt(0),
+		ExecutorOperatorSetId: 1,
+		Status:                uint8(TaskStatusCreated),
+		ExecutorOperatorSetTaskConfig: ExecutorOperatorSetTaskConfig{
+			TaskSLA:      big.NewInt(60),
+			TaskMetadata: []byte{},
+		},
+		Payload:      payload,
+		ExecutorCert: []byte{},
+		Result:       []byte{},
+	}
+}
+
+// callBackend answers every eth_call with a fixed return value.
+type callBackend struct {
+	bind.ContractBackend
+	ret []byte
+	err error
+}
+
+func (b *callBackend) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return b.ret, b.err
+}
+
+func (b *callBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{0x60}, nil
+}
+
+func Test_GetTaskInfo(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(taskMailboxABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := createdTask()
+	ret, err := parsed.Methods["getTaskInfo"].Outputs.Pack(want)
+	if err != nil {
+		t.Fatalf("failed to pack task: %v", err)
+	}
+
+	mailbox, err := NewTaskMailbox(common.Address{}, &callBackend{ret: ret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := mailbox.GetTaskInfo(context.Background(), taskHash)
+	if err != nil {
+		t.Fatalf("GetTaskInfo: %v", err)
+	}
+	if got.Avs != want.Avs || got.ExecutorOperatorSetId != 1 || got.TaskStatus() != TaskStatusCreated {
+		t.Errorf("unexpected task %+v", got)
+	}
+	if string(got.Payload) != string(payload) || got.ExecutorOperatorSetTaskConfig.TaskSLA.Int64() != 60 {
+		t.Errorf("unexpected task %+v", got)
+	}
+}
+
+type fakeMailbox map[common.Hash]*Task
+
+func (f fakeMailbox) GetTaskInfo(_ context.Context, hash common.Hash) (*Task, error) {
+	if task, ok := f[hash]; ok {
+		return task, nil
+	}
+	return &Task{}, nil
+}
+
+func Test_Verify(t *testing.T) {
+	operatorSetId := uint32(1)
+
+	tests := []struct {
+		name      string
+		task      func(*Task)
+		taskId    []byte
+		payload   []byte
+		wantField string
+	}{
+		{
+			name: "matching task",
+		},
+		{
+			name:      "unknown task",
+			taskId:    crypto.Keccak256([]byte("fabricated")),
+			wantField: "task",
+		},
+		{
+			name:      "task ID is not a task hash",
+			taskId:    []byte("test-task-id"),
+			wantField: "task ID",
+		},
+		{
+			name:      "different payload",
+			payload:   []byte("goodbye world"),
+			wantField: "payload hash",
+		},
+		{
+			name:      "different AVS",
+			task:      func(task *Task) { task.Avs = otherAvs },
+			wantField: "AVS",
+		},
+		{
+			name:      "different operator set",
+			task:      func(task *Task) { task.ExecutorOperatorSetId = 2 },
+			wantField: "executor operator set",
+		},
+		{
+			name:      "already verified",
+			task:      func(task *Task) { task.Status = uint8(TaskStatusVerified) },
+			wantField: "status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := createdTask()
+			if tt.task != nil {
+				tt.task(task)
+			}
+			taskId := taskHash.Bytes()
+			if tt.taskId != nil {
+				taskId = tt.taskId
+			}
+			req := payload
+			if tt.payload != nil {
+				req = tt.payload
+			}
+
+			v := NewVerifier(fakeMailbox{taskHash: task}, avs, &operatorSetId)
+			got, err := v.Verify(context.Background(), taskId, req)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				if got != task {
+					t.Errorf("expected the stored task to be returned")
+				}
+				return
+			}
+
+			var mismatch *MismatchError
+			if !errors.As(err, &mismatch) || !errors.Is(err, ErrTaskMismatch) {
+				t.Fatalf("expected a MismatchError, got %v", err)
+			}
+			if mismatch.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", mismatch.Field, tt.wantField)
+			}
+			if code := status.Code(err); code != codes.PermissionDenied {
+				t.Errorf("gRPC code = %s, want PermissionDenied", code)
+			}
+		})
+	}
+}
+
+func Test_VerifyAnyOperatorSet(t *testing.T) {
+	task := createdTask()
+	task.ExecutorOperatorSetId = 7
+
+	v := NewVerifier(fakeMailbox{taskHash: task}, avs, nil)
+	if _, err := v.Verify(context.Background(), taskHash.Bytes(), payload); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func Test_VerifierConfigFromEnv(t *testing.T) {
+	t.Setenv("PERFORMER_VERIFY_TASKS", "true")
+	t.Setenv("AVS_ADDRESS", avs.Hex())
+	t.Setenv("EXECUTOR_OPERATOR_SET_ID", "1")
+
+	cfg, err := VerifierConfigFromEnv()
+	if err != nil {
+		t.Fatalf("VerifierConfigFromEnv: %v", err)
+	}
+	if !cfg.Enabled || cfg.Avs != avs || cfg.ExecutorOperatorSetId == nil || *cfg.ExecutorOperatorSetId != 1 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	t.Setenv("EXECUTOR_OPERATOR_SET_ID", "one")
+	if _, err := VerifierConfigFromEnv(); err == nil {
+		t.Errorf("expected an error for an invalid operator set ID")
+	}
+}
+
+func Test_OperatorSetKey(t *testing.T) {
+	set := OperatorSet{Avs: avs, Id: 7}
+	want := common.HexToHash("0xa000000000000000000000000000000000000001000000000000000000000007")
+	if got := set.Key(); got != want {
+		t.Errorf("Key() = %s, want %s", got, want)
+	}
+}
+
+func Test_TaskConfigFile(t *testing.T) {
+	f, err := ParseTaskConfigFile([]byte(`{
+		"executorOperatorSetId": 1,
+		"taskHook": "0xd000000000000000000000000000000000000004",
+		"taskSLA": "2m",
+		"curveType": "bn254",
+		"consensus": {"type": "STAKE_PROPORTION_THRESHOLD", "threshold": 6667}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	declared, err := f.TaskConfig()
+	if err != nil {
+		t.Fatalf("TaskConfig: %v", err)
+	}
+	if declared.TaskSLA.Int64() != 120 || declared.CurveType != 2 {
+		t.Errorf("unexpected task config %+v", declared)
+	}
+	if err := ValidateTaskConfig(declared, big.NewInt(3600)); err != nil {
+		t.Fatalf("ValidateTaskConfig: %v", err)
+	}
+	if err := ValidateTaskConfig(declared, big.NewInt(60)); err == nil || !strings.Contains(err.Error(), "MAX_TASK_SLA") {
+		t.Errorf("expected the SLA to exceed MAX_TASK_SLA, got %v", err)
+	}
+
+	current := declared
+	current.TaskSLA = big.NewInt(60)
+	current.Consensus = StakeProportionThreshold(MaxStakeProportionThreshold)
+	changes := DiffTaskConfig(current, declared)
+	if len(changes) != 2 || changes[0].Field != "taskSLA" || changes[1].Field != "consensus" {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if changes[1].Current != "STAKE_PROPORTION_THRESHOLD(10000)" || changes[1].Declared != "STAKE_PROPORTION_THRESHOLD(6667)" {
+		t.Errorf("unexpected consensus change %+v", changes[1])
+	}
+
+	// The file written by `taskconfig show` declares the same config.
+	data, err := yaml.Marshal(NewTaskConfigFile(OperatorSet{Avs: avs, Id: 1}, declared))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shownFile, err := ParseTaskConfigFile(data)
+	if err != nil {
+		t.Fatalf("ParseTaskConfigFile(%s): %v", data, err)
+	}
+	shown, err := shownFile.TaskConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes := DiffTaskConfig(declared, shown); len(changes) != 0 {
+		t.Errorf("round trip changed %+v", changes)
+	}
+}
+
+func Test_TaskConfigFileYAML(t *testing.T) {
+	f, err := ParseTaskConfigFile([]byte(`
+# Executor operator set 1
+executorOperatorSetId: 1
+taskHook: 0xd000000000000000000000000000000000000004
+taskSLA: 2m
+feeToken: 0x0000000000000000000000000000000000000000  # no fee
+feeCollector: "0x0000000000000000000000000000000000000000"
+curveType: BN254
+consensus: {type: STAKE_PROPORTION_THRESHOLD, threshold: 6667}
+taskMetadata: 0x7b7d
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	declared, err := f.TaskConfig()
+	if err != nil {
+		t.Fatalf("TaskConfig: %v", err)
+	}
+	if declared.TaskHook != common.HexToAddress("0xd000000000000000000000000000000000000004") ||
+		declared.TaskSLA.Int64() != 120 || string(declared.TaskMetadata) != "{}" {
+		t.Errorf("unexpected task config %+v", declared)
+	}
+
+	// Hex values are strings in every YAML form, and unquoted in JSON
+	for _, doc := range []string{
+		"consensus: {type: STAKE_PROPORTION_THRESHOLD, value: 0x0000000000000000000000000000000000000000000000000000000000002710}",
+		"consensus:\n  type: STAKE_PROPORTION_THRESHOLD\n  value: 0x0000000000000000000000000000000000000000000000000000000000002710",
+		`{"consensus": {"type": "STAKE_PROPORTION_THRESHOLD", "value": 0x0000000000000000000000000000000000000000000000000000000000002710}}`,
+	} {
+		f, err := ParseTaskConfigFile([]byte(doc))
+		if err != nil {
+			t.Fatalf("ParseTaskConfigFile(%s): %v", doc, err)
+		}
+		if got := hexutil.Encode(f.Consensus.Value); !strings.HasSuffix(got, "2710") || len(f.Consensus.Value) != 32 {
+			t.Errorf("ParseTaskConfigFile(%s): consensus value %s", doc, got)
+		}
+	}
+	if f, err := ParseTaskConfigFile([]byte(`{"feeToken": 0x00, "taskMetadata": 0x00}`)); err == nil {
+		t.Errorf("expected 0x00 to be rejected as an address, got %+v", f)
+	}
+	if f, err := ParseTaskConfigFile([]byte(`{"taskMetadata": 0x00}`)); err != nil || len(f.TaskMetadata) != 1 {
+		t.Errorf("expected unquoted 0x00 to be one byte of task metadata, got %+v, %v", f, err)
+	}
+	if f, err := ParseTaskConfigFile(nil); err != nil || f.ExecutorOperatorSetId != 0 {
+		t.Errorf("expected an empty file to parse, got %+v, %v", f, err)
+	}
+
+	if _, err := ParseTaskConfigFile([]byte("executorOperatorSetId: 1\ntaskTimeout: 2m\n")); err == nil {
+		t.Error("expected an unknown field to be rejected")
+	}
+}
+
+func Test_ValidateConsensus(t *testing.T) {
+	tests := []struct {
+		name      string
+		consensus Consensus
+		ok        bool
+	}{
+		{"threshold", StakeProportionThreshold(5000), true},
+		{"threshold over 100%", StakeProportionThreshold(10_001), false},
+		{"packed uint16", Consensus{ConsensusType: uint8(ConsensusTypeStakeProportionThreshold), Value: []byte{0x27, 0x10}}, false},
+		{"wider than uint16", Consensus{ConsensusType: uint8(ConsensusTypeStakeProportionThreshold), Value: common.LeftPadBytes([]byte{1, 0, 0}, 32)}, false},
+		{"none", Consensus{ConsensusType: uint8(ConsensusTypeNone)}, true},
+		{"none with value", Consensus{ConsensusType: uint8(ConsensusTypeNone), Value: []byte{1}}, false},
+		{"unknown type", Consensus{ConsensusType: 9}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateConsensus(tt.consensus); (err == nil) != tt.ok {
+				t.Errorf("ValidateConsensus() = %v, want ok = %t", err, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_GetExecutorOperatorSetTaskConfig(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(taskMailboxABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := createdTask().ExecutorOperatorSetTaskConfig
+	want.Consensus = StakeProportionThreshold(10_000)
+	ret, err := parsed.Methods["getExecutorOperatorSetTaskConfig"].Outputs.Pack(want)
+	if err != nil {
+		t.Fatalf("failed to pack task config: %v", err)
+	}
+
+	mailbox, err := NewTaskMailbox(common.Address{}, &callBackend{ret: ret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := mailbox.GetExecutorOperatorSetTaskConfig(context.Background(), OperatorSet{Avs: avs, Id: 1})
+	if err != nil {
+		t.Fatalf("GetExecutorOperatorSetTaskConfig: %v", err)
+	}
+	if changes := DiffTaskConfig(*got, want); len(changes) != 0 {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+}
+
+// revertError is an RPC error carrying revert data.
+type revertError struct {
+	data []byte
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+func Test_TaskMailboxRevertsAreDecoded(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(taskMailboxABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalidStatus := parsed.Errors["InvalidTaskStatus"]
+	args, err := invalidStatus.Inputs.Pack(uint8(TaskStatusCreated), uint8(TaskStatusVerified))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &callBackend{err: &revertError{data: append(invalidStatus.ID[:4], args...)}}
+	mailbox, err := NewTaskMailbox(common.Address{}, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = mailbox.GetTaskInfo(context.Background(), taskHash)
+	if !reverts.Is(err, "InvalidTaskStatus") {
+		t.Fatalf("expected a decoded InvalidTaskStatus revert, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "TaskMailbox.InvalidTaskStatus(expected: CREATED, actual: VERIFIED)") {
+		t.Errorf("unexpected message %q", err)
+	}
+}
+
+// logBackend answers eth_getLogs with fixed logs and records the query.
+type logBackend struct {
+	bind.ContractBackend
+	logs  []types.Log
+	query ethereum.FilterQuery
+}
+
+func (b *logBackend) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	b.query = q
+	return b.logs, nil
+}
+
+func Test_FilterTaskEvents(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(taskMailboxABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	creator := common.HexToAddress("0xc0")
+
+	created := parsed.Events["TaskCreated"]
+	data, err := created.Inputs.NonIndexed().Pack(uint32(1), uint32(7), common.Address{}, big.NewInt(5), big.NewInt(100), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &logBackend{logs: []types.Log{{
+		Topics:      []common.Hash{created.ID, common.BytesToHash(creator.Bytes()), taskHash, common.BytesToHash(avs.Bytes())},
+		Data:        data,
+		BlockNumber: 42,
+	}}}
+	m, err := NewTaskMailbox(common.Address{}, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := m.FilterTaskCreated(context.Background(), 10, 50, avs)
+	if err != nil {
+		t.Fatalf("FilterTaskCreated: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.Creator != creator || ev.TaskHash != taskHash || ev.Avs != avs || ev.ExecutorOperatorSetId != 1 {
+		t.Errorf("unexpected indexed fields %+v", ev)
+	}
+	if string(ev.Payload) != string(payload) || ev.AvsFee.Int64() != 5 || ev.Raw.BlockNumber != 42 {
+		t.Errorf("unexpected event %+v", ev)
+	}
+	if backend.query.FromBlock.Uint64() != 10 || backend.query.ToBlock.Uint64() != 50 || backend.query.Topics[3][0] != common.BytesToHash(avs.Bytes()) {
+		t.Errorf("unexpected query %+v", backend.query)
+	}
+
+	verified := parsed.Events["TaskVerified"]
+	if data, err = verified.Inputs.NonIndexed().Pack(uint32(1), []byte{0xce}, []byte("result")); err != nil {
+		t.Fatal(err)
+	}
+	backend.logs = []types.Log{{
+		Topics: []common.Hash{verified.ID, {}, taskHash, common.BytesToHash(avs.Bytes())},
+		Data:   data,
+	}}
+	results, err := m.FilterTaskVerified(context.Background(), 10, 50, avs)
+	if err != nil {
+		t.Fatalf("FilterTaskVerified: %v", err)
+	}
+	if len(results) != 1 || results[0].TaskHash != taskHash || string(results[0].Result) != "result" {
+		t.Errorf("unexpected events %+v", results)
+	}
+}
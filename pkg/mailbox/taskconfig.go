@@ -0,0 +1,325 @@
+package mailbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/operator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	yaml "sigs.k8s.io/yaml/goyaml.v3"
+)
+
+// ConsensusType is the TaskMailbox's consensus rule for task results.
+type ConsensusType uint8
+
+const (
+	ConsensusTypeNone ConsensusType = iota
+	ConsensusTypeStakeProportionThreshold
+)
+
+func (c ConsensusType) String() string {
+	switch c {
+	case ConsensusTypeNone:
+		return "NONE"
+	case ConsensusTypeStakeProportionThreshold:
+		return "STAKE_PROPORTION_THRESHOLD"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", uint8(c))
+	}
+}
+
+// ParseConsensusType parses a consensus type name as returned by
+// ConsensusType.String.
+func ParseConsensusType(s string) (ConsensusType, error) {
+	for _, c := range []ConsensusType{ConsensusTypeNone, ConsensusTypeStakeProportionThreshold} {
+		if strings.EqualFold(s, c.String()) {
+			return c, nil
+		}
+	}
+	return ConsensusTypeNone, fmt.Errorf("unknown consensus type %q (expected NONE or STAKE_PROPORTION_THRESHOLD)", s)
+}
+
+// MaxStakeProportionThreshold is 100% in basis points.
+const MaxStakeProportionThreshold = 10_000
+
+// StakeProportionThreshold encodes threshold, in basis points, as the
+// consensus value of a STAKE_PROPORTION_THRESHOLD rule: abi.encode(uint16).
+func StakeProportionThreshold(threshold uint16) Consensus {
+	value := make([]byte, 32)
+	big.NewInt(int64(threshold)).FillBytes(value)
+	return Consensus{ConsensusType: uint8(ConsensusTypeStakeProportionThreshold), Value: value}
+}
+
+// ValidateConsensus checks that c's value is encoded the way the TaskMailbox
+// decodes it for c's type.
+func ValidateConsensus(c Consensus) error {
+	switch ConsensusType(c.ConsensusType) {
+	case ConsensusTypeNone:
+		if len(c.Value) != 0 {
+			return fmt.Errorf("consensus type NONE must have an empty value, got %d bytes", len(c.Value))
+		}
+	case ConsensusTypeStakeProportionThreshold:
+		threshold, err := decodeThreshold(c.Value)
+		if err != nil {
+			return err
+		}
+		if threshold > MaxStakeProportionThreshold {
+			return fmt.Errorf("stake proportion threshold %d exceeds %d basis points", threshold, MaxStakeProportionThreshold)
+		}
+	default:
+		return fmt.Errorf("unknown consensus type %d", c.ConsensusType)
+	}
+	return nil
+}
+
+func decodeThreshold(value []byte) (uint64, error) {
+	if len(value) != 32 {
+		return 0, fmt.Errorf("stake proportion threshold must be abi.encode(uint16), got %d bytes", len(value))
+	}
+	v := new(big.Int).SetBytes(value)
+	if v.BitLen() > 16 {
+		return 0, fmt.Errorf("stake proportion threshold 0x%x does not fit in a uint16", value)
+	}
+	return v.Uint64(), nil
+}
+
+// ValidateTaskConfig checks cfg before it is sent to the TaskMailbox. maxSLA
+// is the TaskMailbox's MAX_TASK_SLA in seconds.
+func ValidateTaskConfig(cfg ExecutorOperatorSetTaskConfig, maxSLA *big.Int) error {
+	if cfg.TaskHook == (common.Address{}) {
+		return fmt.Errorf("taskHook is required")
+	}
+	if cfg.TaskSLA == nil || cfg.TaskSLA.Sign() <= 0 {
+		return fmt.Errorf("taskSLA must be positive")
+	}
+	if maxSLA != nil && cfg.TaskSLA.Cmp(maxSLA) > 0 {
+		return fmt.Errorf("taskSLA %s exceeds the TaskMailbox's MAX_TASK_SLA of %s", formatSLA(cfg.TaskSLA), formatSLA(maxSLA))
+	}
+	if curve := operator.CurveType(cfg.CurveType); curve != operator.CurveTypeECDSA && curve != operator.CurveTypeBN254 {
+		return fmt.Errorf("curveType must be ECDSA or BN254")
+	}
+	if cfg.FeeToken != (common.Address{}) && cfg.FeeCollector == (common.Address{}) {
+		return fmt.Errorf("feeCollector is required when feeToken is set")
+	}
+	return ValidateConsensus(cfg.Consensus)
+}
+
+// ConsensusFile is the consensus rule in a TaskConfigFile. A
+// STAKE_PROPORTION_THRESHOLD rule takes either a threshold in basis points or
+// its encoded value.
+type ConsensusFile struct {
+	Type      string        `json:"type"`
+	Threshold *uint16       `json:"threshold,omitempty"`
+	Value     hexutil.Bytes `json:"value,omitempty"`
+}
+
+// TaskConfigFile is the declared task configuration of an executor operator
+// set, in YAML or JSON:
+//
+//	executorOperatorSetId: 1
+//	taskHook: 0x...
+//	taskSLA: 60s
+//	feeToken: 0x0000000000000000000000000000000000000000
+//	feeCollector: 0x0000000000000000000000000000000000000000
+//	curveType: BN254
+//	consensus: {type: STAKE_PROPORTION_THRESHOLD, threshold: 10000}
+//	taskMetadata: 0x
+//
+// avs may also be set; by default it is the sender's address.
+type TaskConfigFile struct {
+	Avs                   *common.Address `json:"avs,omitempty"`
+	ExecutorOperatorSetId uint32          `json:"executorOperatorSetId"`
+	TaskHook              common.Address  `json:"taskHook"`
+	TaskSLA               string          `json:"taskSLA"`
+	FeeToken              common.Address  `json:"feeToken"`
+	FeeCollector          common.Address  `json:"feeCollector"`
+	CurveType             string          `json:"curveType"`
+	Consensus             ConsensusFile   `json:"consensus"`
+	TaskMetadata          hexutil.Bytes   `json:"taskMetadata"`
+}
+
+// ParseTaskConfigFile parses a task config file. JSON is valid YAML, so
+// either format is accepted. The document is converted to JSON and decoded
+// with the struct's JSON tags, rejecting unknown fields.
+func ParseTaskConfigFile(data []byte) (*TaskConfigFile, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var v any
+	if doc.Kind != 0 {
+		hexScalarsAsStrings(&doc)
+		if err := doc.Decode(&v); err != nil {
+			return nil, err
+		}
+	}
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var f TaskConfigFile
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// hexScalarsAsStrings tags every unquoted 0x scalar under n as a string.
+// YAML reads them as integers, so 0x0000000000000000000000000000000000000000
+// would become 0; every hex value in a task config is an address or bytes.
+func hexScalarsAsStrings(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) == 0 &&
+		(strings.HasPrefix(n.Value, "0x") || strings.HasPrefix(n.Value, "0X")) {
+		n.Tag = "!!str"
+	}
+	for _, c := range n.Content {
+		hexScalarsAsStrings(c)
+	}
+}
+
+// LoadTaskConfigFile reads and parses the task config file at path.
+func LoadTaskConfigFile(path string) (*TaskConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ParseTaskConfigFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task config file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// TaskConfig converts f to the TaskMailbox's representation.
+func (f *TaskConfigFile) TaskConfig() (ExecutorOperatorSetTaskConfig, error) {
+	sla, err := time.ParseDuration(f.TaskSLA)
+	if err != nil {
+		return ExecutorOperatorSetTaskConfig{}, fmt.Errorf("invalid taskSLA: %w", err)
+	}
+	if sla%time.Second != 0 {
+		return ExecutorOperatorSetTaskConfig{}, fmt.Errorf("taskSLA %s is not a whole number of seconds", sla)
+	}
+	curve, err := operator.ParseCurveType(f.CurveType)
+	if err != nil {
+		return ExecutorOperatorSetTaskConfig{}, err
+	}
+	consensus, err := f.Consensus.consensus()
+	if err != nil {
+		return ExecutorOperatorSetTaskConfig{}, err
+	}
+	metadata := []byte(f.TaskMetadata)
+	if metadata == nil {
+		metadata = []byte{}
+	}
+	return ExecutorOperatorSetTaskConfig{
+		TaskHook:     f.TaskHook,
+		TaskSLA:      big.NewInt(int64(sla / time.Second)),
+		FeeToken:     f.FeeToken,
+		CurveType:    uint8(curve),
+		FeeCollector: f.FeeCollector,
+		Consensus:    consensus,
+		TaskMetadata: metadata,
+	}, nil
+}
+
+func (c ConsensusFile) consensus() (Consensus, error) {
+	typ, err := ParseConsensusType(c.Type)
+	if err != nil {
+		return Consensus{}, err
+	}
+	if c.Threshold != nil {
+		if typ != ConsensusTypeStakeProportionThreshold {
+			return Consensus{}, fmt.Errorf("consensus threshold is only valid for STAKE_PROPORTION_THRESHOLD")
+		}
+		if c.Value != nil {
+			return Consensus{}, fmt.Errorf("consensus takes either threshold or value, not both")
+		}
+		return StakeProportionThreshold(*c.Threshold), nil
+	}
+	value := []byte(c.Value)
+	if value == nil {
+		value = []byte{}
+	}
+	return Consensus{ConsensusType: uint8(typ), Value: value}, nil
+}
+
+// NewTaskConfigFile describes cfg, the task configuration of set, as a task
+// config file.
+func NewTaskConfigFile(set OperatorSet, cfg ExecutorOperatorSetTaskConfig) *TaskConfigFile {
+	avs := set.Avs
+	f := &TaskConfigFile{
+		Avs:                   &avs,
+		ExecutorOperatorSetId: set.Id,
+		TaskHook:              cfg.TaskHook,
+		TaskSLA:               formatSLA(cfg.TaskSLA),
+		FeeToken:              cfg.FeeToken,
+		FeeCollector:          cfg.FeeCollector,
+		CurveType:             operator.CurveType(cfg.CurveType).String(),
+		Consensus:             ConsensusFile{Type: ConsensusType(cfg.Consensus.ConsensusType).String()},
+		TaskMetadata:          cfg.TaskMetadata,
+	}
+	if threshold, err := decodeThreshold(cfg.Consensus.Value); err == nil && ConsensusType(cfg.Consensus.ConsensusType) == ConsensusTypeStakeProportionThreshold {
+		t := uint16(threshold)
+		f.Consensus.Threshold = &t
+	} else if len(cfg.Consensus.Value) > 0 {
+		f.Consensus.Value = cfg.Consensus.Value
+	}
+	return f
+}
+
+// TaskConfigChange is a field whose current and declared values differ.
+type TaskConfigChange struct {
+	Field    string `json:"field"`
+	Current  string `json:"current"`
+	Declared string `json:"declared"`
+}
+
+// DiffTaskConfig lists the fields that differ between current and declared.
+func DiffTaskConfig(current, declared ExecutorOperatorSetTaskConfig) []TaskConfigChange {
+	var changes []TaskConfigChange
+	add := func(field, current, declared string) {
+		if current != declared {
+			changes = append(changes, TaskConfigChange{Field: field, Current: current, Declared: declared})
+		}
+	}
+	add("taskHook", current.TaskHook.Hex(), declared.TaskHook.Hex())
+	add("taskSLA", formatSLA(current.TaskSLA), formatSLA(declared.TaskSLA))
+	add("feeToken", current.FeeToken.Hex(), declared.FeeToken.Hex())
+	add("feeCollector", current.FeeCollector.Hex(), declared.FeeCollector.Hex())
+	add("curveType", operator.CurveType(current.CurveType).String(), operator.CurveType(declared.CurveType).String())
+	add("consensus", formatConsensus(current.Consensus), formatConsensus(declared.Consensus))
+	add("taskMetadata", hexutil.Encode(current.TaskMetadata), hexutil.Encode(declared.TaskMetadata))
+	return changes
+}
+
+func formatSLA(seconds *big.Int) string {
+	if seconds == nil {
+		return "0s"
+	}
+	if !seconds.IsInt64() || seconds.Int64() > int64(1<<63-1)/int64(time.Second) {
+		return seconds.String() + "s"
+	}
+	return (time.Duration(seconds.Int64()) * time.Second).String()
+}
+
+func formatConsensus(c Consensus) string {
+	typ := ConsensusType(c.ConsensusType)
+	if typ == ConsensusTypeStakeProportionThreshold {
+		if threshold, err := decodeThreshold(c.Value); err == nil {
+			return fmt.Sprintf("%s(%d)", typ, threshold)
+		}
+	}
+	if len(c.Value) == 0 {
+		return typ.String()
+	}
+	return fmt.Sprintf("%s(%s)", typ, hexutil.Encode(c.Value))
+}
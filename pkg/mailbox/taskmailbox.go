@@ -0,0 +1,268 @@
+// Package mailbox reads task state from the Hourglass TaskMailbox on L2 and
+// checks TaskRequests against it.
+package mailbox
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"strings"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/reverts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// The TaskMailbox is part of the EigenLayer core contracts, not this
+// repository, so only the functions the Performer uses are bound here.
+
+const taskConfigTuple = `{"name":"executorOperatorSetTaskConfig","type":"tuple","components":[
+	{"name":"taskHook","type":"address"},
+	{"name":"taskSLA","type":"uint96"},
+	{"name":"feeToken","type":"address"},
+	{"name":"curveType","type":"uint8"},
+	{"name":"feeCollector","type":"address"},
+	{"name":"consensus","type":"tuple","components":[{"name":"consensusType","type":"uint8"},{"name":"value","type":"bytes"}]},
+	{"name":"taskMetadata","type":"bytes"}
+]}`
+
+const operatorSetTuple = `{"name":"operatorSet","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]}`
+
+const taskMailboxABI = `[
+	{"type":"function","name":"getTaskInfo","stateMutability":"view","inputs":[{"name":"taskHash","type":"bytes32"}],"outputs":[
+		{"name":"","type":"tuple","components":[
+			{"name":"creator","type":"address"},
+			{"name":"creationTime","type":"uint96"},
+			{"name":"avs","type":"address"},
+			{"name":"avsFee","type":"uint96"},
+			{"name":"refundCollector","type":"address"},
+			{"name":"executorOperatorSetId","type":"uint32"},
+			{"name":"feeSplit","type":"uint16"},
+			{"name":"status","type":"uint8"},
+			{"name":"isFeeRefunded","type":"bool"},
+			{"name":"operatorTableReferenceTimestamp","type":"uint32"},
+			` + taskConfigTuple + `,
+			{"name":"payload","type":"bytes"},
+			{"name":"executorCert","type":"bytes"},
+			{"name":"result","type":"bytes"}
+		]}
+	]},
+	{"type":"function","name":"getExecutorOperatorSetTaskConfig","stateMutability":"view","inputs":[` + operatorSetTuple + `],"outputs":[` + taskConfigTuple + `]},
+	{"type":"function","name":"setExecutorOperatorSetTaskConfig","stateMutability":"nonpayable","inputs":[` + operatorSetTuple + `,` + taskConfigTuple + `],"outputs":[]},
+	{"type":"function","name":"isExecutorOperatorSetRegistered","stateMutability":"view","inputs":[{"name":"operatorSetKey","type":"bytes32"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"registerExecutorOperatorSet","stateMutability":"nonpayable","inputs":[` + operatorSetTuple + `,{"name":"isRegistered","type":"bool"}],"outputs":[]},
+	{"type":"function","name":"MAX_TASK_SLA","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint96"}]},
+	{"type":"event","name":"TaskCreated","anonymous":false,"inputs":[
+		{"name":"creator","type":"address","indexed":true},
+		{"name":"taskHash","type":"bytes32","indexed":true},
+		{"name":"avs","type":"address","indexed":true},
+		{"name":"executorOperatorSetId","type":"uint32","indexed":false},
+		{"name":"operatorTableReferenceTimestamp","type":"uint32","indexed":false},
+		{"name":"refundCollector","type":"address","indexed":false},
+		{"name":"avsFee","type":"uint96","indexed":false},
+		{"name":"taskDeadline","type":"uint256","indexed":false},
+		{"name":"payload","type":"bytes","indexed":false}
+	]},
+	{"type":"event","name":"TaskVerified","anonymous":false,"inputs":[
+		{"name":"aggregator","type":"address","indexed":true},
+		{"name":"taskHash","type":"bytes32","indexed":true},
+		{"name":"avs","type":"address","indexed":true},
+		{"name":"executorOperatorSetId","type":"uint32","indexed":false},
+		{"name":"executorCert","type":"bytes","indexed":false},
+		{"name":"result","type":"bytes","indexed":false}
+	]},
+	{"type":"error","name":"CertificateStale","inputs":[]},
+	{"type":"error","name":"EmptyCertificateSignature","inputs":[]},
+	{"type":"error","name":"ExecutorOperatorSetNotRegistered","inputs":[]},
+	{"type":"error","name":"ExecutorOperatorSetTaskConfigNotSet","inputs":[]},
+	{"type":"error","name":"FeeAlreadyRefunded","inputs":[]},
+	{"type":"error","name":"InvalidAddressZero","inputs":[]},
+	{"type":"error","name":"InvalidConsensusType","inputs":[]},
+	{"type":"error","name":"InvalidConsensusValue","inputs":[]},
+	{"type":"error","name":"InvalidCurveType","inputs":[]},
+	{"type":"error","name":"InvalidFeeReceiver","inputs":[]},
+	{"type":"error","name":"InvalidFeeSplit","inputs":[]},
+	{"type":"error","name":"InvalidMessageHash","inputs":[]},
+	{"type":"error","name":"InvalidOperatorSetOwner","inputs":[]},
+	{"type":"error","name":"InvalidReferenceTimestamp","inputs":[]},
+	{"type":"error","name":"InvalidShortString","inputs":[]},
+	{"type":"error","name":"InvalidTaskCreator","inputs":[]},
+	{"type":"error","name":"InvalidTaskStatus","inputs":[{"name":"expected","type":"uint8"},{"name":"actual","type":"uint8"}]},
+	{"type":"error","name":"OnlyRefundCollector","inputs":[]},
+	{"type":"error","name":"PayloadIsEmpty","inputs":[]},
+	{"type":"error","name":"StringTooLong","inputs":[{"name":"str","type":"string"}]},
+	{"type":"error","name":"TaskSLAExceedsMaximum","inputs":[]},
+	{"type":"error","name":"ThresholdNotMet","inputs":[]},
+	{"type":"error","name":"TimestampAtCreation","inputs":[]}
+]`
+
+func init() {
+	// Decode the TaskMailbox's custom errors wherever its calls revert
+	if parsed, err := abi.JSON(strings.NewReader(taskMailboxABI)); err == nil {
+		reverts.Register("TaskMailbox", parsed)
+		taskStatus := func(v uint8) string { return TaskStatus(v).String() }
+		reverts.RegisterEnum("InvalidTaskStatus", "expected", taskStatus)
+		reverts.RegisterEnum("InvalidTaskStatus", "actual", taskStatus)
+	}
+}
+
+// TaskStatus is the TaskMailbox's lifecycle state of a task.
+type TaskStatus uint8
+
+const (
+	TaskStatusNone TaskStatus = iota
+	TaskStatusCreated
+	TaskStatusVerified
+	TaskStatusExpired
+)
+
+func (s TaskStatus) String() string {
+	switch s {
+	case TaskStatusNone:
+		return "NONE"
+	case TaskStatusCreated:
+		return "CREATED"
+	case TaskStatusVerified:
+		return "VERIFIED"
+	case TaskStatusExpired:
+		return "EXPIRED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// OperatorSet identifies an operator set of an AVS.
+type OperatorSet struct {
+	Avs common.Address
+	Id  uint32
+}
+
+// Key returns the operator set's key, abi.encodePacked(avs, uint96(id)), as
+// computed by OperatorSetLib.
+func (s OperatorSet) Key() common.Hash {
+	var key common.Hash
+	copy(key[:common.AddressLength], s.Avs.Bytes())
+	binary.BigEndian.PutUint32(key[common.HashLength-4:], s.Id)
+	return key
+}
+
+// Consensus is the consensus rule of an executor operator set.
+type Consensus struct {
+	ConsensusType uint8
+	Value         []byte
+}
+
+// ExecutorOperatorSetTaskConfig is the task configuration of an executor
+// operator set, as stored with each task at creation.
+type ExecutorOperatorSetTaskConfig struct {
+	TaskHook     common.Address
+	TaskSLA      *big.Int
+	FeeToken     common.Address
+	CurveType    uint8
+	FeeCollector common.Address
+	Consensus    Consensus
+	TaskMetadata []byte
+}
+
+// Task is a task as returned by getTaskInfo.
+type Task struct {
+	Creator                         common.Address
+	CreationTime                    *big.Int
+	Avs                             common.Address
+	AvsFee                          *big.Int
+	RefundCollector                 common.Address
+	ExecutorOperatorSetId           uint32
+	FeeSplit                        uint16
+	Status                          uint8
+	IsFeeRefunded                   bool
+	OperatorTableReferenceTimestamp uint32
+	ExecutorOperatorSetTaskConfig   ExecutorOperatorSetTaskConfig
+	Payload                         []byte
+	ExecutorCert                    []byte
+	Result                          []byte
+}
+
+// TaskStatus returns the task's status.
+func (t *Task) TaskStatus() TaskStatus {
+	return TaskStatus(t.Status)
+}
+
+// TaskMailbox is a minimal binding to the Hourglass TaskMailbox.
+type TaskMailbox struct {
+	address  common.Address
+	abi      abi.ABI
+	contract *bind.BoundContract
+	filterer bind.ContractFilterer
+}
+
+func NewTaskMailbox(address common.Address, backend bind.ContractBackend) (*TaskMailbox, error) {
+	parsed, err := abi.JSON(strings.NewReader(taskMailboxABI))
+	if err != nil {
+		return nil, err
+	}
+	return &TaskMailbox{
+		address:  address,
+		abi:      parsed,
+		contract: bind.NewBoundContract(address, parsed, backend, backend, backend),
+		filterer: backend,
+	}, nil
+}
+
+// Address returns the TaskMailbox address.
+func (m *TaskMailbox) Address() common.Address {
+	return m.address
+}
+
+// GetTaskInfo returns the task with the given hash. Unknown tasks are
+// returned with status NONE and a zero creator.
+func (m *TaskMailbox) GetTaskInfo(ctx context.Context, taskHash common.Hash) (*Task, error) {
+	var out []any
+	if err := m.contract.Call(&bind.CallOpts{Context: ctx}, &out, "getTaskInfo", taskHash); err != nil {
+		return nil, reverts.Decode(err)
+	}
+	return abi.ConvertType(out[0], new(Task)).(*Task), nil
+}
+
+// GetExecutorOperatorSetTaskConfig returns the task configuration of set.
+// Operator sets without one are returned with a zero task hook.
+func (m *TaskMailbox) GetExecutorOperatorSetTaskConfig(ctx context.Context, set OperatorSet) (*ExecutorOperatorSetTaskConfig, error) {
+	var out []any
+	if err := m.contract.Call(&bind.CallOpts{Context: ctx}, &out, "getExecutorOperatorSetTaskConfig", set); err != nil {
+		return nil, reverts.Decode(err)
+	}
+	return abi.ConvertType(out[0], new(ExecutorOperatorSetTaskConfig)).(*ExecutorOperatorSetTaskConfig), nil
+}
+
+// IsExecutorOperatorSetRegistered reports whether set may receive tasks.
+func (m *TaskMailbox) IsExecutorOperatorSetRegistered(ctx context.Context, set OperatorSet) (bool, error) {
+	var out []any
+	if err := m.contract.Call(&bind.CallOpts{Context: ctx}, &out, "isExecutorOperatorSetRegistered", set.Key()); err != nil {
+		return false, reverts.Decode(err)
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// MaxTaskSLA returns the largest task SLA, in seconds, the TaskMailbox accepts.
+func (m *TaskMailbox) MaxTaskSLA(ctx context.Context) (*big.Int, error) {
+	var out []any
+	if err := m.contract.Call(&bind.CallOpts{Context: ctx}, &out, "MAX_TASK_SLA"); err != nil {
+		return nil, reverts.Decode(err)
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// SetExecutorOperatorSetTaskConfig sets the task configuration of set. It
+// must be sent by the AVS or an appointee.
+func (m *TaskMailbox) SetExecutorOperatorSetTaskConfig(opts *bind.TransactOpts, set OperatorSet, cfg ExecutorOperatorSetTaskConfig) (*types.Transaction, error) {
+	tx, err := m.contract.Transact(opts, "setExecutorOperatorSetTaskConfig", set, cfg)
+	return tx, reverts.Decode(err)
+}
+
+// RegisterExecutorOperatorSet registers or deregisters set as an executor
+// operator set. Its task configuration must be set first.
+func (m *TaskMailbox) RegisterExecutorOperatorSet(opts *bind.TransactOpts, set OperatorSet, registered bool) (*types.Transaction, error) {
+	tx, err := m.contract.Transact(opts, "registerExecutorOperatorSet", set, registered)
+	return tx, reverts.Decode(err)
+}
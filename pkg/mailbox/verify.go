@@ -0,0 +1,163 @@
+package mailbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrTaskMismatch is matched (via errors.Is) by every error returned when a
+// TaskRequest does not match the task stored in the TaskMailbox.
+var ErrTaskMismatch = errors.New("task does not match the TaskMailbox")
+
+// MismatchError is returned when a TaskRequest disagrees with the TaskMailbox.
+// It carries the gRPC PermissionDenied code so that spoofed tasks can be told
+// apart from task failures.
+type MismatchError struct {
+	TaskHash common.Hash
+	Field    string
+	Want     string
+	Got      string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s: task %s: %s is %s, want %s", ErrTaskMismatch, e.TaskHash, e.Field, e.Got, e.Want)
+}
+
+func (e *MismatchError) Is(target error) bool {
+	return target == ErrTaskMismatch
+}
+
+// GRPCStatus allows the gRPC server to map this error to codes.PermissionDenied.
+func (e *MismatchError) GRPCStatus() *status.Status {
+	return status.New(codes.PermissionDenied, e.Error())
+}
+
+// TaskInfoReader reads tasks from the TaskMailbox. *TaskMailbox implements it.
+type TaskInfoReader interface {
+	GetTaskInfo(ctx context.Context, taskHash common.Hash) (*Task, error)
+}
+
+// VerifierConfig configures task verification.
+type VerifierConfig struct {
+	Enabled bool
+	// Avs is the AVS tasks must be created for. If zero, the Performer reads
+	// it from the TaskAVSRegistrar.
+	Avs common.Address
+	// ExecutorOperatorSetId, if set, is the operator set tasks must be
+	// created for.
+	ExecutorOperatorSetId *uint32
+}
+
+// VerifierConfigFromEnv reads PERFORMER_VERIFY_TASKS, AVS_ADDRESS and
+// EXECUTOR_OPERATOR_SET_ID.
+func VerifierConfigFromEnv() (VerifierConfig, error) {
+	var cfg VerifierConfig
+	if v := os.Getenv("PERFORMER_VERIFY_TASKS"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return VerifierConfig{}, fmt.Errorf("invalid PERFORMER_VERIFY_TASKS %q: %w", v, err)
+		}
+		cfg.Enabled = enabled
+	}
+	if v := os.Getenv("AVS_ADDRESS"); v != "" {
+		if !common.IsHexAddress(v) {
+			return VerifierConfig{}, fmt.Errorf("invalid AVS_ADDRESS %q", v)
+		}
+		cfg.Avs = common.HexToAddress(v)
+	}
+	if v := os.Getenv("EXECUTOR_OPERATOR_SET_ID"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return VerifierConfig{}, fmt.Errorf("invalid EXECUTOR_OPERATOR_SET_ID %q: %w", v, err)
+		}
+		id32 := uint32(id)
+		cfg.ExecutorOperatorSetId = &id32
+	}
+	return cfg, nil
+}
+
+// Verifier checks that a TaskRequest is a task that was created in the
+// TaskMailbox for this AVS and is still waiting for a result.
+type Verifier struct {
+	mailbox               TaskInfoReader
+	avs                   common.Address
+	executorOperatorSetId *uint32
+}
+
+// NewVerifier creates a Verifier. If executorOperatorSetId is nil, tasks for
+// any of the AVS's operator sets are accepted.
+func NewVerifier(mailbox TaskInfoReader, avs common.Address, executorOperatorSetId *uint32) *Verifier {
+	return &Verifier{
+		mailbox:               mailbox,
+		avs:                   avs,
+		executorOperatorSetId: executorOperatorSetId,
+	}
+}
+
+// Verify treats taskId as the TaskMailbox task hash, reads the task and checks
+// it against payload. It returns the stored task, or a *MismatchError if the
+// request does not match it.
+func (v *Verifier) Verify(ctx context.Context, taskId []byte, payload []byte) (*Task, error) {
+	if err := checkTaskId(taskId); err != nil {
+		return nil, err
+	}
+	hash := common.BytesToHash(taskId)
+
+	task, err := v.mailbox.GetTaskInfo(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task %s from the TaskMailbox: %w", hash, err)
+	}
+	if err := v.Check(taskId, task, payload); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Check compares a task already read from the TaskMailbox with the request's
+// task ID and payload.
+func (v *Verifier) Check(taskId []byte, task *Task, payload []byte) error {
+	if err := checkTaskId(taskId); err != nil {
+		return err
+	}
+	hash := common.BytesToHash(taskId)
+
+	mismatch := func(field string, want any, got any) error {
+		return &MismatchError{TaskHash: hash, Field: field, Want: fmt.Sprint(want), Got: fmt.Sprint(got)}
+	}
+
+	if task.TaskStatus() == TaskStatusNone {
+		return mismatch("task", "created", "not found")
+	}
+	if task.Avs != v.avs {
+		return mismatch("AVS", v.avs, task.Avs)
+	}
+	if v.executorOperatorSetId != nil && task.ExecutorOperatorSetId != *v.executorOperatorSetId {
+		return mismatch("executor operator set", *v.executorOperatorSetId, task.ExecutorOperatorSetId)
+	}
+	if want, got := crypto.Keccak256Hash(task.Payload), crypto.Keccak256Hash(payload); want != got {
+		return mismatch("payload hash", want, got)
+	}
+	if task.TaskStatus() != TaskStatusCreated {
+		return mismatch("status", TaskStatusCreated, task.TaskStatus())
+	}
+	return nil
+}
+
+func checkTaskId(taskId []byte) error {
+	if len(taskId) != common.HashLength {
+		return &MismatchError{
+			Field: "task ID",
+			Want:  fmt.Sprintf("a %d-byte task hash", common.HashLength),
+			Got:   fmt.Sprintf("%d bytes", len(taskId)),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Package metrics holds the Performer's metrics registry and serves it in the
+// Prometheus text format.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+)
+
+// Registry contains every metric recorded by the Performer.
+var Registry = metrics.NewRegistry()
+
+var (
+	// TasksPanicked counts tasks whose handler panicked and was recovered.
+	TasksPanicked = metrics.NewRegisteredCounter("performer/tasks/panicked", Registry)
+
+	// TasksRejected counts tasks rejected because the Performer was at capacity.
+	TasksRejected = metrics.NewRegisteredCounter("performer/tasks/rejected", Registry)
+
+	// TasksUnverified counts tasks rejected because they did not match the
+	// TaskMailbox.
+	TasksUnverified = metrics.NewRegisteredCounter("performer/tasks/unverified", Registry)
+
+	// TasksLate counts tasks rejected because they could not finish before
+	// their deadline.
+	TasksLate = metrics.NewRegisteredCounter("performer/tasks/late", Registry)
+
+	// TasksDenied counts tasks rejected by the creator policy.
+	TasksDenied = metrics.NewRegisteredCounter("performer/tasks/denied", Registry)
+
+	// TasksUnderpaid counts tasks rejected because they did not pay the
+	// minimum fee.
+	TasksUnderpaid = metrics.NewRegisteredCounter("performer/tasks/underpaid", Registry)
+
+	// TasksDisabled counts tasks rejected because the AVS disabled their
+	// handler in the task metadata.
+	TasksDisabled = metrics.NewRegisteredCounter("performer/tasks/disabled", Registry)
+
+	// TasksDiverged counts handled tasks whose result differs from the one
+	// certified in their TaskVerified event.
+	TasksDiverged = metrics.NewRegisteredCounter("performer/tasks/diverged", Registry)
+)
+
+// Serve exposes Registry on addr at /metrics until ctx is done.
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prometheus.Handler(Registry))
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/taskavsregistrar"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/reverts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// The EigenLayer core contracts are not part of this repository, so only the
+// functions the registration flow needs are bound here, with the custom errors
+// those functions and their mixins can revert with.
+
+const operatorSetTuple = `{"name":"operatorSet","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"id","type":"uint32"}]}`
+
+const allocationManagerABI = `[
+	{"type":"function","name":"registerForOperatorSets","stateMutability":"nonpayable","inputs":[
+		{"name":"operator","type":"address"},
+		{"name":"params","type":"tuple","components":[{"name":"avs","type":"address"},{"name":"operatorSetIds","type":"uint32[]"},{"name":"data","type":"bytes"}]}
+	],"outputs":[]},
+	{"type":"function","name":"deregisterFromOperatorSets","stateMutability":"nonpayable","inputs":[
+		{"name":"params","type":"tuple","components":[{"name":"operator","type":"address"},{"name":"avs","type":"address"},{"name":"operatorSetIds","type":"uint32[]"}]}
+	],"outputs":[]},
+	{"type":"function","name":"isMemberOfOperatorSet","stateMutability":"view","inputs":[
+		{"name":"operator","type":"address"},` + operatorSetTuple + `
+	],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"error","name":"AlreadyMemberOfSet","inputs":[]},
+	{"type":"error","name":"CurrentlyPaused","inputs":[]},
+	{"type":"error","name":"InputArrayLengthMismatch","inputs":[]},
+	{"type":"error","name":"InvalidAVSRegistrar","inputs":[]},
+	{"type":"error","name":"InvalidCaller","inputs":[]},
+	{"type":"error","name":"InvalidOperator","inputs":[]},
+	{"type":"error","name":"InvalidOperatorSet","inputs":[]},
+	{"type":"error","name":"InvalidPermissions","inputs":[]},
+	{"type":"error","name":"NotMemberOfSet","inputs":[]}
+]`
+
+const keyRegistrarABI = `[
+	{"type":"function","name":"getOperatorSetCurveType","stateMutability":"view","inputs":[` + operatorSetTuple + `],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"isRegistered","stateMutability":"view","inputs":[` + operatorSetTuple + `,{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"getECDSAKeyRegistrationMessageHash","stateMutability":"view","inputs":[
+		{"name":"operator","type":"address"},` + operatorSetTuple + `,{"name":"keyAddress","type":"address"}
+	],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"getBN254KeyRegistrationMessageHash","stateMutability":"view","inputs":[
+		{"name":"operator","type":"address"},` + operatorSetTuple + `,{"name":"keyData","type":"bytes"}
+	],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"registerKey","stateMutability":"nonpayable","inputs":[
+		{"name":"operator","type":"address"},` + operatorSetTuple + `,{"name":"pubkey","type":"bytes"},{"name":"signature","type":"bytes"}
+	],"outputs":[]},
+	{"type":"error","name":"ConfigurationAlreadySet","inputs":[]},
+	{"type":"error","name":"InvalidCurveType","inputs":[]},
+	{"type":"error","name":"InvalidKeyFormat","inputs":[]},
+	{"type":"error","name":"InvalidKeypair","inputs":[]},
+	{"type":"error","name":"InvalidPermissions","inputs":[]},
+	{"type":"error","name":"InvalidSignature","inputs":[]},
+	{"type":"error","name":"KeyAlreadyRegistered","inputs":[]},
+	{"type":"error","name":"KeyNotFound","inputs":[` + operatorSetTuple + `,{"name":"operator","type":"address"}]},
+	{"type":"error","name":"OperatorSetNotConfigured","inputs":[]},
+	{"type":"error","name":"OperatorStillSlashable","inputs":[` + operatorSetTuple + `,{"name":"operator","type":"address"}]},
+	{"type":"error","name":"SignatureExpired","inputs":[]},
+	{"type":"error","name":"ZeroPubkey","inputs":[]}
+]`
+
+func init() {
+	// Decode the AllocationManager's and KeyRegistrar's custom errors
+	// wherever registration and socket calls revert
+	if parsed, err := abi.JSON(strings.NewReader(allocationManagerABI)); err == nil {
+		reverts.Register("AllocationManager", parsed)
+	}
+	if parsed, err := abi.JSON(strings.NewReader(keyRegistrarABI)); err == nil {
+		reverts.Register("KeyRegistrar", parsed)
+	}
+}
+
+// CurveType is the KeyRegistrar's key type for an operator set.
+type CurveType uint8
+
+const (
+	CurveTypeNone CurveType = iota
+	CurveTypeECDSA
+	CurveTypeBN254
+)
+
+func (c CurveType) String() string {
+	switch c {
+	case CurveTypeECDSA:
+		return "ECDSA"
+	case CurveTypeBN254:
+		return "BN254"
+	default:
+		return "NONE"
+	}
+}
+
+// ParseCurveType parses a curve type name as returned by CurveType.String.
+func ParseCurveType(s string) (CurveType, error) {
+	for _, c := range []CurveType{CurveTypeNone, CurveTypeECDSA, CurveTypeBN254} {
+		if strings.EqualFold(s, c.String()) {
+			return c, nil
+		}
+	}
+	return CurveTypeNone, fmt.Errorf("unknown curve type %q (expected ECDSA or BN254)", s)
+}
+
+type registerParams struct {
+	Avs            common.Address
+	OperatorSetIds []uint32
+	Data           []byte
+}
+
+type deregisterParams struct {
+	Operator       common.Address
+	Avs            common.Address
+	OperatorSetIds []uint32
+}
+
+// AllocationManager is a minimal binding to EigenLayer's AllocationManager.
+type AllocationManager struct {
+	contract *bind.BoundContract
+}
+
+func NewAllocationManager(address common.Address, backend bind.ContractBackend) (*AllocationManager, error) {
+	parsed, err := abi.JSON(strings.NewReader(allocationManagerABI))
+	if err != nil {
+		return nil, err
+	}
+	return &AllocationManager{contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+func (m *AllocationManager) IsMemberOfOperatorSet(ctx context.Context, operator common.Address, set taskavsregistrar.OperatorSet) (bool, error) {
+	var out []any
+	if err := m.contract.Call(&bind.CallOpts{Context: ctx}, &out, "isMemberOfOperatorSet", operator, set); err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+func (m *AllocationManager) RegisterForOperatorSets(opts *bind.TransactOpts, operator common.Address, avs common.Address, operatorSetIds []uint32, data []byte) (*types.Transaction, error) {
+	return m.contract.Transact(opts, "registerForOperatorSets", operator, registerParams{Avs: avs, OperatorSetIds: operatorSetIds, Data: data})
+}
+
+func (m *AllocationManager) DeregisterFromOperatorSets(opts *bind.TransactOpts, operator common.Address, avs common.Address, operatorSetIds []uint32) (*types.Transaction, error) {
+	return m.contract.Transact(opts, "deregisterFromOperatorSets", deregisterParams{Operator: operator, Avs: avs, OperatorSetIds: operatorSetIds})
+}
+
+// KeyRegistrar is a minimal binding to EigenLayer's KeyRegistrar.
+type KeyRegistrar struct {
+	contract *bind.BoundContract
+}
+
+func NewKeyRegistrar(address common.Address, backend bind.ContractBackend) (*KeyRegistrar, error) {
+	parsed, err := abi.JSON(strings.NewReader(keyRegistrarABI))
+	if err != nil {
+		return nil, err
+	}
+	return &KeyRegistrar{contract: bind.NewBoundContract(address, parsed, backend, backend, backend)}, nil
+}
+
+func (r *KeyRegistrar) GetOperatorSetCurveType(ctx context.Context, set taskavsregistrar.OperatorSet) (CurveType, error) {
+	var out []any
+	if err := r.contract.Call(&bind.CallOpts{Context: ctx}, &out, "getOperatorSetCurveType", set); err != nil {
+		return CurveTypeNone, err
+	}
+	return CurveType(*abi.ConvertType(out[0], new(uint8)).(*uint8)), nil
+}
+
+func (r *KeyRegistrar) IsRegistered(ctx context.Context, set taskavsregistrar.OperatorSet, operator common.Address) (bool, error) {
+	var out []any
+	if err := r.contract.Call(&bind.CallOpts{Context: ctx}, &out, "isRegistered", set, operator); err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+func (r *KeyRegistrar) GetECDSAKeyRegistrationMessageHash(ctx context.Context, operator common.Address, set taskavsregistrar.OperatorSet, keyAddress common.Address) ([32]byte, error) {
+	return r.messageHash(ctx, "getECDSAKeyRegistrationMessageHash", operator, set, keyAddress)
+}
+
+func (r *KeyRegistrar) GetBN254KeyRegistrationMessageHash(ctx context.Context, operator common.Address, set taskavsregistrar.OperatorSet, keyData []byte) ([32]byte, error) {
+	return r.messageHash(ctx, "getBN254KeyRegistrationMessageHash", operator, set, keyData)
+}
+
+func (r *KeyRegistrar) messageHash(ctx context.Context, method string, params ...any) ([32]byte, error) {
+	var out []any
+	if err := r.contract.Call(&bind.CallOpts{Context: ctx}, &out, method, params...); err != nil {
+		return [32]byte{}, fmt.Errorf("%s failed: %w", method, err)
+	}
+	return *abi.ConvertType(out[0], new([32]byte)).(*[32]byte), nil
+}
+
+func (r *KeyRegistrar) RegisterKey(opts *bind.TransactOpts, operator common.Address, set taskavsregistrar.OperatorSet, pubkey []byte, signature []byte) (*types.Transaction, error) {
+	return r.contract.Transact(opts, "registerKey", operator, set, pubkey, signature)
+}
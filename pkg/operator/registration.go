@@ -0,0 +1,333 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/taskavsregistrar"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/bn254"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HashSigner signs raw message hashes. signer.PrivateKeySigner implements it.
+type HashSigner interface {
+	Address() common.Address
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// Keys is the key material an operator registers with the KeyRegistrar. Only
+// the key matching each operator set's curve type is required.
+type Keys struct {
+	// ECDSA is the key registered for ECDSA operator sets.
+	ECDSA HashSigner
+
+	// ECDSASource describes where ECDSA came from, e.g. a key file or the
+	// transaction signer. It is reported with the key registration.
+	ECDSASource string
+
+	// BN254 is the key registered for BN254 operator sets.
+	BN254 *bn254.PrivateKey
+}
+
+// Registrar drives registration through EigenLayer's AllocationManager, which
+// calls into the TaskAVSRegistrar.
+type Registrar struct {
+	Avs               common.Address
+	TaskAVSRegistrar  *taskavsregistrar.TaskAVSRegistrar
+	AllocationManager *AllocationManager
+	KeyRegistrar      *KeyRegistrar
+}
+
+// NewRegistrar binds the TaskAVSRegistrar at address and the AVS,
+// AllocationManager and KeyRegistrar it is configured with.
+func NewRegistrar(ctx context.Context, address common.Address, backend bind.ContractBackend) (*Registrar, error) {
+	registrar, err := taskavsregistrar.NewTaskAVSRegistrar(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	avs, err := registrar.Avs(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avs from TaskAVSRegistrar %s: %w", address, err)
+	}
+	allocationManagerAddr, err := registrar.AllocationManager(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allocationManager from TaskAVSRegistrar: %w", err)
+	}
+	keyRegistrarAddr, err := registrar.KeyRegistrar(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyRegistrar from TaskAVSRegistrar: %w", err)
+	}
+
+	allocationManager, err := NewAllocationManager(allocationManagerAddr, backend)
+	if err != nil {
+		return nil, err
+	}
+	keyRegistrar, err := NewKeyRegistrar(keyRegistrarAddr, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Registrar{
+		Avs:               avs,
+		TaskAVSRegistrar:  registrar,
+		AllocationManager: allocationManager,
+		KeyRegistrar:      keyRegistrar,
+	}, nil
+}
+
+func (r *Registrar) operatorSet(id uint32) taskavsregistrar.OperatorSet {
+	return taskavsregistrar.OperatorSet{Avs: r.Avs, Id: id}
+}
+
+// SetStatus is an operator's standing in one operator set.
+type SetStatus struct {
+	OperatorSetId uint32    `json:"operatorSetId"`
+	CurveType     CurveType `json:"-"`
+	Curve         string    `json:"curveType"`
+	Allowed       bool      `json:"allowed"`
+	KeyRegistered bool      `json:"keyRegistered"`
+	Registered    bool      `json:"registered"`
+}
+
+// Status is an operator's registration status across operator sets.
+type Status struct {
+	Operator common.Address `json:"operator"`
+	Avs      common.Address `json:"avs"`
+	Socket   string         `json:"socket"`
+	Sets     []SetStatus    `json:"operatorSets"`
+}
+
+// Status reports allowlist, key and membership status for operator in each
+// of the given operator sets.
+func (r *Registrar) Status(ctx context.Context, operator common.Address, operatorSetIds []uint32) (*Status, error) {
+	socket, err := GetSocket(ctx, r.TaskAVSRegistrar, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Operator: operator, Avs: r.Avs, Socket: socket}
+	for _, id := range operatorSetIds {
+		set := r.operatorSet(id)
+		s := SetStatus{OperatorSetId: id}
+
+		if s.Allowed, err = r.TaskAVSRegistrar.IsOperatorAllowed(&bind.CallOpts{Context: ctx}, set, operator); err != nil {
+			return nil, fmt.Errorf("isOperatorAllowed failed for operator set %d: %w", id, err)
+		}
+		if s.CurveType, err = r.KeyRegistrar.GetOperatorSetCurveType(ctx, set); err != nil {
+			return nil, fmt.Errorf("getOperatorSetCurveType failed for operator set %d: %w", id, err)
+		}
+		s.Curve = s.CurveType.String()
+		if s.KeyRegistered, err = r.KeyRegistrar.IsRegistered(ctx, set, operator); err != nil {
+			return nil, fmt.Errorf("isRegistered failed for operator set %d: %w", id, err)
+		}
+		if s.Registered, err = r.AllocationManager.IsMemberOfOperatorSet(ctx, operator, set); err != nil {
+			return nil, fmt.Errorf("isMemberOfOperatorSet failed for operator set %d: %w", id, err)
+		}
+		status.Sets = append(status.Sets, s)
+	}
+	return status, nil
+}
+
+// RegistrationData encodes socket as abi.encode(string), the data the
+// TaskAVSRegistrar decodes in registerOperator.
+func RegistrationData(socket string) ([]byte, error) {
+	if err := ValidateSocket(socket); err != nil {
+		return nil, err
+	}
+	stringType, _ := abi.NewType("string", "", nil)
+	return abi.Arguments{{Type: stringType}}.Pack(socket)
+}
+
+// KeyRegistration is a signed KeyRegistrar.registerKey call.
+type KeyRegistration struct {
+	OperatorSetId uint32        `json:"operatorSetId"`
+	CurveType     CurveType     `json:"-"`
+	Curve         string        `json:"curveType"`
+	Pubkey        hexutil.Bytes `json:"pubkey"`
+	Signature     hexutil.Bytes `json:"signature"`
+
+	// KeySource is where the registered key came from, if known.
+	KeySource string `json:"keySource,omitempty"`
+}
+
+// Plan is everything a registration will submit, built without sending any
+// transactions so it can be printed for a dry run.
+type Plan struct {
+	Status           *Status           `json:"status"`
+	OperatorSetIds   []uint32          `json:"operatorSetIds"`
+	Data             hexutil.Bytes     `json:"data"`
+	KeyRegistrations []KeyRegistration `json:"keyRegistrations"`
+}
+
+// PlanRegistration checks operator may join the given operator sets and
+// builds the key registrations and registration data. Sets the operator is
+// already a member of are skipped.
+func (r *Registrar) PlanRegistration(ctx context.Context, operator common.Address, operatorSetIds []uint32, socket string, keys Keys) (*Plan, error) {
+	data, err := RegistrationData(socket)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := r.Status(ctx, operator, operatorSetIds)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Status: status, Data: data}
+	for _, s := range status.Sets {
+		if s.Registered {
+			continue
+		}
+		if !s.Allowed {
+			return nil, fmt.Errorf("operator %s is not on the allowlist for operator set %d", operator, s.OperatorSetId)
+		}
+		plan.OperatorSetIds = append(plan.OperatorSetIds, s.OperatorSetId)
+
+		if s.KeyRegistered {
+			continue
+		}
+		reg, err := r.signKeyRegistration(ctx, operator, s.OperatorSetId, s.CurveType, keys)
+		if err != nil {
+			return nil, err
+		}
+		plan.KeyRegistrations = append(plan.KeyRegistrations, *reg)
+	}
+	return plan, nil
+}
+
+func (r *Registrar) signKeyRegistration(ctx context.Context, operator common.Address, id uint32, curve CurveType, keys Keys) (*KeyRegistration, error) {
+	set := r.operatorSet(id)
+	reg := &KeyRegistration{OperatorSetId: id, CurveType: curve, Curve: curve.String()}
+
+	switch curve {
+	case CurveTypeECDSA:
+		if keys.ECDSA == nil {
+			return nil, fmt.Errorf("operator set %d uses ECDSA keys but no ECDSA key was given", id)
+		}
+		keyAddress := keys.ECDSA.Address()
+		hash, err := r.KeyRegistrar.GetECDSAKeyRegistrationMessageHash(ctx, operator, set, keyAddress)
+		if err != nil {
+			return nil, err
+		}
+		if reg.Signature, err = keys.ECDSA.SignHash(hash[:]); err != nil {
+			return nil, fmt.Errorf("failed to sign ECDSA key registration: %w", err)
+		}
+		reg.Pubkey = keyAddress.Bytes()
+		reg.KeySource = keys.ECDSASource
+
+	case CurveTypeBN254:
+		if keys.BN254 == nil {
+			return nil, fmt.Errorf("operator set %d uses BN254 keys but no BN254 key was given", id)
+		}
+		keyData := bn254.KeyData(keys.BN254.PublicKeyG1(), keys.BN254.PublicKeyG2())
+		hash, err := r.KeyRegistrar.GetBN254KeyRegistrationMessageHash(ctx, operator, set, keyData)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := keys.BN254.SignHash(hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign BN254 key registration: %w", err)
+		}
+		reg.Pubkey = keyData
+		reg.Signature = sig.Marshal()
+
+	default:
+		return nil, fmt.Errorf("operator set %d has no curve type configured in the KeyRegistrar", id)
+	}
+	return reg, nil
+}
+
+// Registration is the outcome of a confirmed registration or deregistration.
+type Registration struct {
+	Operator       common.Address `json:"operator"`
+	OperatorSetIds []uint32       `json:"operatorSetIds"`
+	KeyTxHashes    []common.Hash  `json:"keyTxHashes,omitempty"`
+	TxHash         common.Hash    `json:"txHash"`
+	Block          uint64         `json:"block"`
+}
+
+// Register submits the plan's key registrations, then registerForOperatorSets
+// on the AllocationManager. It succeeds only once the receipt contains the
+// TaskAVSRegistrar's OperatorRegistered event.
+func (r *Registrar) Register(ctx context.Context, tr *signer.Transactor, operator common.Address, plan *Plan) (*Registration, error) {
+	if len(plan.OperatorSetIds) == 0 {
+		return nil, fmt.Errorf("operator %s is already registered for every requested operator set", operator)
+	}
+
+	result := &Registration{Operator: operator, OperatorSetIds: plan.OperatorSetIds}
+	for _, key := range plan.KeyRegistrations {
+		receipt, err := tr.SendAndWait(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return r.KeyRegistrar.RegisterKey(opts, operator, r.operatorSet(key.OperatorSetId), key.Pubkey, key.Signature)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("registerKey failed for operator set %d: %w", key.OperatorSetId, err)
+		}
+		result.KeyTxHashes = append(result.KeyTxHashes, receipt.TxHash)
+	}
+
+	receipt, err := tr.SendAndWait(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return r.AllocationManager.RegisterForOperatorSets(opts, operator, r.Avs, plan.OperatorSetIds, plan.Data)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("registerForOperatorSets failed: %w", err)
+	}
+
+	for _, log := range receipt.Logs {
+		event, err := r.TaskAVSRegistrar.ParseOperatorRegistered(*log)
+		if err != nil {
+			continue
+		}
+		if event.Operator == operator && sameIds(event.OperatorSetIds, plan.OperatorSetIds) {
+			result.TxHash = receipt.TxHash
+			result.Block = receipt.BlockNumber.Uint64()
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s was mined but emitted no OperatorRegistered event for %s", receipt.TxHash, operator)
+}
+
+// Deregister submits deregisterFromOperatorSets on the AllocationManager and
+// checks for the TaskAVSRegistrar's OperatorDeregistered event.
+func (r *Registrar) Deregister(ctx context.Context, tr *signer.Transactor, operator common.Address, operatorSetIds []uint32) (*Registration, error) {
+	receipt, err := tr.SendAndWait(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return r.AllocationManager.DeregisterFromOperatorSets(opts, operator, r.Avs, operatorSetIds)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("deregisterFromOperatorSets failed: %w", err)
+	}
+
+	for _, log := range receipt.Logs {
+		event, err := r.TaskAVSRegistrar.ParseOperatorDeregistered(*log)
+		if err != nil {
+			continue
+		}
+		if event.Operator == operator && sameIds(event.OperatorSetIds, operatorSetIds) {
+			return &Registration{
+				Operator:       operator,
+				OperatorSetIds: operatorSetIds,
+				TxHash:         receipt.TxHash,
+				Block:          receipt.BlockNumber.Uint64(),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s was mined but emitted no OperatorDeregistered event for %s", receipt.TxHash, operator)
+}
+
+func sameIds(a []uint32, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
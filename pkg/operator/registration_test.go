@@ -0,0 +1,74 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/taskavsregistrar"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/reverts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_RegistrationData(t *testing.T) {
+	data, err := RegistrationData("executor:9090")
+	if err != nil {
+		t.Fatalf("RegistrationData failed: %v", err)
+	}
+
+	// Same encoding as `cast abi-encode "f(string)" executor:9090`
+	stringType, _ := abi.NewType("string", "", nil)
+	out, err := abi.Arguments{{Type: stringType}}.Unpack(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].(string) != "executor:9090" {
+		t.Fatalf("expected executor:9090, got %v", out[0])
+	}
+
+	if _, err := RegistrationData("not a socket"); err == nil {
+		t.Fatal("expected an error for an invalid socket")
+	}
+}
+
+func Test_EigenLayerABIs(t *testing.T) {
+	am, err := abi.JSON(strings.NewReader(allocationManagerABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	operator := common.HexToAddress("0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65")
+	avs := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	// Selectors of the EigenLayer functions
+	if _, err := am.Pack("registerForOperatorSets", operator, registerParams{Avs: avs, OperatorSetIds: []uint32{1}, Data: []byte{1}}); err != nil {
+		t.Fatalf("failed to pack registerForOperatorSets: %v", err)
+	}
+	if got := common.Bytes2Hex(am.Methods["registerForOperatorSets"].ID); got != "adc2e3d9" {
+		t.Fatalf("unexpected registerForOperatorSets selector %s", got)
+	}
+	if _, err := am.Pack("deregisterFromOperatorSets", deregisterParams{Operator: operator, Avs: avs, OperatorSetIds: []uint32{1}}); err != nil {
+		t.Fatalf("failed to pack deregisterFromOperatorSets: %v", err)
+	}
+
+	kr, err := abi.JSON(strings.NewReader(keyRegistrarABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := taskavsregistrar.OperatorSet{Avs: avs, Id: 1}
+	if _, err := kr.Pack("registerKey", operator, set, operator.Bytes(), make([]byte, 65)); err != nil {
+		t.Fatalf("failed to pack registerKey: %v", err)
+	}
+
+	// Their custom errors decode wherever a call reverts
+	data, err := kr.Errors["KeyNotFound"].Inputs.Pack(set, operator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revert := reverts.Default.DecodeData(append(kr.Errors["KeyNotFound"].ID.Bytes()[:4], data...))
+	if revert.Contract != "KeyRegistrar" || revert.Name != "KeyNotFound" {
+		t.Errorf("expected a decoded KeyRegistrar.KeyNotFound, got %q", revert.Error())
+	}
+	if revert := reverts.Default.DecodeData(am.Errors["AlreadyMemberOfSet"].ID.Bytes()[:4]); revert.Name != "AlreadyMemberOfSet" {
+		t.Errorf("expected a decoded AlreadyMemberOfSet, got %q", revert.Error())
+	}
+}
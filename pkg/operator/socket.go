@@ -0,0 +1,153 @@
+// Package operator manages an operator's registration with the AVS through
+// the TaskAVSRegistrar.
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Layr-Labs/hourglass-avs-template/contracts/bindings/l1/taskavsregistrar"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/signer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxSocketLength bounds sockets stored on-chain; anything longer is almost
+// certainly a mistake.
+const maxSocketLength = 255
+
+// socketSchemes are the URL schemes accepted in front of host:port.
+var socketSchemes = map[string]bool{
+	"http":  true,
+	"https": true,
+	"grpc":  true,
+	"grpcs": true,
+}
+
+// ValidateSocket checks that socket is a host:port pair, optionally prefixed
+// with an http, https, grpc or grpcs scheme, e.g. "executor:9090" or
+// "https://executor.example.com:443".
+func ValidateSocket(socket string) error {
+	if socket == "" {
+		return fmt.Errorf("socket is empty")
+	}
+	if len(socket) > maxSocketLength {
+		return fmt.Errorf("socket is longer than %d characters", maxSocketLength)
+	}
+	if strings.ContainsAny(socket, " \t\r\n") {
+		return fmt.Errorf("socket %q contains whitespace", socket)
+	}
+
+	hostPort := socket
+	if scheme, rest, ok := strings.Cut(socket, "://"); ok {
+		if !socketSchemes[strings.ToLower(scheme)] {
+			return fmt.Errorf("socket %q has unsupported scheme %q", socket, scheme)
+		}
+		u, err := url.Parse(socket)
+		if err != nil {
+			return fmt.Errorf("socket %q is not a valid URL: %w", socket, err)
+		}
+		if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+			return fmt.Errorf("socket %q must not contain a path, query or credentials", socket)
+		}
+		hostPort = strings.TrimSuffix(rest, "/")
+	}
+
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return fmt.Errorf("socket %q must be host:port: %w", socket, err)
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("socket %q has invalid port %q", socket, port)
+	}
+	if net.ParseIP(host) == nil && !isHostname(host) {
+		return fmt.Errorf("socket %q has invalid host %q", socket, host)
+	}
+	return nil
+}
+
+// isHostname reports whether host is a valid DNS name (RFC 1123 labels).
+func isHostname(host string) bool {
+	if host == "" || len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// GetSocket returns the socket the registrar stores for operator.
+func GetSocket(ctx context.Context, registrar *taskavsregistrar.TaskAVSRegistrar, operator common.Address) (string, error) {
+	socket, err := registrar.GetOperatorSocket(&bind.CallOpts{Context: ctx}, operator)
+	if err != nil {
+		return "", fmt.Errorf("failed to get socket for %s: %w", operator, err)
+	}
+	return socket, nil
+}
+
+// SocketUpdate is the outcome of a confirmed UpdateSocket transaction.
+type SocketUpdate struct {
+	Operator common.Address
+	Previous string
+	Socket   string
+	TxHash   common.Hash
+	Block    uint64
+}
+
+// UpdateSocket validates socket, submits UpdateSocket for operator and waits
+// for the transaction to be mined. It succeeds only once the receipt contains
+// the matching OperatorSocketSet event.
+func UpdateSocket(
+	ctx context.Context,
+	registrar *taskavsregistrar.TaskAVSRegistrar,
+	tr *signer.Transactor,
+	operator common.Address,
+	socket string,
+) (*SocketUpdate, error) {
+	if err := ValidateSocket(socket); err != nil {
+		return nil, err
+	}
+
+	previous, err := GetSocket(ctx, registrar, operator)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := tr.SendAndWait(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return registrar.UpdateSocket(opts, operator, socket)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("UpdateSocket failed: %w", err)
+	}
+
+	for _, log := range receipt.Logs {
+		event, err := registrar.ParseOperatorSocketSet(*log)
+		if err != nil {
+			// Not an OperatorSocketSet event
+			continue
+		}
+		if event.Operator == operator && event.Socket == socket {
+			return &SocketUpdate{
+				Operator: operator,
+				Previous: previous,
+				Socket:   socket,
+				TxHash:   receipt.TxHash,
+				Block:    receipt.BlockNumber.Uint64(),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s was mined but emitted no OperatorSocketSet event for %s", receipt.TxHash, operator)
+}
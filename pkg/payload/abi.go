@@ -0,0 +1,312 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ABICodec encodes payloads with the Solidity ABI, the same encoding produced
+// by abi.encode(...) in CreateTask.s.sol. Input is a JSON array with one
+// element per type; integers may be given as numbers or decimal/hex strings,
+// bytes as hex strings.
+type ABICodec struct {
+	Types     string
+	Arguments abi.Arguments
+}
+
+// NewABICodec parses a comma separated list of Solidity types such as
+// "string,uint256,address[]". Each type may be followed by a name, e.g.
+// "string message". The list may be wrapped in parentheses, matching the
+// signature passed to `devkit avs call`, e.g. "(uint256,string)".
+func NewABICodec(types string) (*ABICodec, error) {
+	list := strings.TrimSpace(types)
+	if strings.HasPrefix(list, "(") && strings.HasSuffix(list, ")") {
+		list = list[1 : len(list)-1]
+	}
+	if strings.TrimSpace(list) == "" {
+		return nil, fmt.Errorf("abi payload format requires a list of types")
+	}
+
+	var args abi.Arguments
+	for i, field := range strings.Split(list, ",") {
+		parts := strings.Fields(field)
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, fmt.Errorf("invalid abi type %q", field)
+		}
+		typ, err := abi.NewType(parts[0], "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid abi type %q: %w", parts[0], err)
+		}
+		name := fmt.Sprintf("arg%d", i)
+		if len(parts) == 2 {
+			name = parts[1]
+		}
+		args = append(args, abi.Argument{Name: name, Type: typ})
+	}
+	return &ABICodec{Types: types, Arguments: args}, nil
+}
+
+func (c *ABICodec) Name() string { return "abi" }
+
+func (c *ABICodec) Encode(input []byte) ([]byte, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return nil, fmt.Errorf("abi payload must be a JSON array: %w", err)
+	}
+	if len(raw) != len(c.Arguments) {
+		return nil, fmt.Errorf("abi payload has %d values, expected %d (%s)", len(raw), len(c.Arguments), c.Types)
+	}
+
+	values := make([]any, len(raw))
+	for i, arg := range c.Arguments {
+		v, err := fromJSON(arg.Type, raw[i])
+		if err != nil {
+			return nil, fmt.Errorf("value for %s (%s): %w", arg.Name, arg.Type, err)
+		}
+		values[i] = v.Interface()
+	}
+	return c.Arguments.Pack(values...)
+}
+
+func (c *ABICodec) Decode(data []byte) (any, error) {
+	values, err := c.Arguments.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = toJSON(c.Arguments[i].Type, reflect.ValueOf(v))
+	}
+	return out, nil
+}
+
+// fromJSON converts a JSON value into the Go type go-ethereum expects when
+// packing typ.
+func fromJSON(typ abi.Type, raw json.RawMessage) (reflect.Value, error) {
+	goType := typ.GetType()
+
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := parseBigInt(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if typ.T == abi.UintTy && n.Sign() < 0 {
+			return reflect.Value{}, fmt.Errorf("negative value for unsigned type")
+		}
+		if err := checkIntRange(typ, n); err != nil {
+			return reflect.Value{}, err
+		}
+		if goType == reflect.TypeOf(&big.Int{}) {
+			return reflect.ValueOf(n), nil
+		}
+		v := reflect.New(goType).Elem()
+		if typ.T == abi.UintTy {
+			v.SetUint(n.Uint64())
+		} else {
+			v.SetInt(n.Int64())
+		}
+		return v, nil
+
+	case abi.BoolTy:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.StringTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(s), nil
+
+	case abi.AddressTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, err
+		}
+		if !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", s)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+
+	case abi.BytesTy, abi.FixedBytesTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, err
+		}
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes %q: %w", s, err)
+		}
+		if typ.T == abi.BytesTy {
+			return reflect.ValueOf(b), nil
+		}
+		if len(b) != typ.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", typ.Size, len(b))
+		}
+		v := reflect.New(goType).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return reflect.Value{}, err
+		}
+		var v reflect.Value
+		if typ.T == abi.SliceTy {
+			v = reflect.MakeSlice(goType, len(elems), len(elems))
+		} else {
+			if len(elems) != typ.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d elements, got %d", typ.Size, len(elems))
+			}
+			v = reflect.New(goType).Elem()
+		}
+		for i, e := range elems {
+			ev, err := fromJSON(*typ.Elem, e)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("unsupported abi type %s", typ)
+}
+
+// Samples returns well-formed payloads for the codec's types: one with zero
+// values and one with non-empty values. They are useful as fuzzing seeds.
+func (c *ABICodec) Samples() ([][]byte, error) {
+	var samples [][]byte
+	for _, filled := range []bool{false, true} {
+		values := make([]any, len(c.Arguments))
+		for i, arg := range c.Arguments {
+			values[i] = sampleValue(arg.Type, filled).Interface()
+		}
+		data, err := c.Arguments.Pack(values...)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, data)
+	}
+	return samples, nil
+}
+
+func sampleValue(typ abi.Type, filled bool) reflect.Value {
+	v := reflect.New(typ.GetType()).Elem()
+	if !filled {
+		if v.Kind() == reflect.Ptr {
+			v.Set(reflect.ValueOf(new(big.Int)))
+		}
+		return v
+	}
+
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		if v.Kind() == reflect.Ptr {
+			v.Set(reflect.ValueOf(big.NewInt(1)))
+		} else if typ.T == abi.UintTy {
+			v.SetUint(1)
+		} else {
+			v.SetInt(1)
+		}
+	case abi.BoolTy:
+		v.SetBool(true)
+	case abi.StringTy:
+		v.SetString("hello")
+	case abi.AddressTy:
+		v.Set(reflect.ValueOf(common.BytesToAddress([]byte{1})))
+	case abi.BytesTy:
+		v.SetBytes([]byte{1, 2, 3})
+	case abi.FixedBytesTy:
+		v.Index(0).SetUint(1)
+	case abi.SliceTy:
+		v = reflect.MakeSlice(v.Type(), 2, 2)
+		fallthrough
+	case abi.ArrayTy:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).Set(sampleValue(*typ.Elem, true))
+		}
+	}
+	return v
+}
+
+// checkIntRange reports whether n fits typ: [0, 2^Size) for uintN and
+// [-2^(Size-1), 2^(Size-1)) for intN.
+func checkIntRange(typ abi.Type, n *big.Int) error {
+	if typ.T == abi.UintTy {
+		if n.BitLen() > typ.Size {
+			return fmt.Errorf("value overflows %d bits", typ.Size)
+		}
+		return nil
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(typ.Size-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return fmt.Errorf("value out of range for int%d", typ.Size)
+	}
+	return nil
+}
+
+// parseBigInt parses a decimal integer, or a hex one with an explicit 0x
+// prefix. Octal, binary and underscores are not accepted, so that a
+// decimal-looking value never becomes a different number.
+func parseBigInt(raw json.RawMessage) (*big.Int, error) {
+	s := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	digits, neg := strings.CutPrefix(s, "-")
+	base := 10
+	if hex, ok := strings.CutPrefix(digits, "0x"); ok {
+		digits, base = hex, 16
+	} else if hex, ok := strings.CutPrefix(digits, "0X"); ok {
+		digits, base = hex, 16
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok || strings.HasPrefix(digits, "+") || strings.HasPrefix(digits, "-") {
+		return nil, fmt.Errorf("invalid integer %s", raw)
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+// toJSON converts unpacked ABI values into JSON friendly values: integers as
+// decimal strings, byte arrays as hex and addresses as checksummed hex.
+func toJSON(typ abi.Type, v reflect.Value) any {
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		if n, ok := v.Interface().(*big.Int); ok {
+			return n.String()
+		}
+		if typ.T == abi.UintTy {
+			return fmt.Sprint(v.Uint())
+		}
+		return fmt.Sprint(v.Int())
+
+	case abi.AddressTy:
+		return v.Interface().(common.Address).Hex()
+
+	case abi.BytesTy, abi.FixedBytesTy:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hexutil.Encode(b)
+
+	case abi.SliceTy, abi.ArrayTy:
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = toJSON(*typ.Elem, v.Index(i))
+		}
+		return out
+	}
+	return v.Interface()
+}
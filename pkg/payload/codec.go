@@ -0,0 +1,104 @@
+// Package payload converts task payloads and results between the raw bytes
+// carried in a TaskRequest/TaskResponse and human-readable forms.
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Codec encodes human-readable input into payload bytes and decodes payload
+// bytes back into a value that can be printed or marshalled to JSON.
+type Codec interface {
+	// Name identifies the codec, e.g. "hex" or "abi".
+	Name() string
+
+	// Encode converts input, as read from a file or stdin, into payload bytes.
+	Encode(input []byte) ([]byte, error)
+
+	// Decode converts payload bytes into a printable value.
+	Decode(data []byte) (any, error)
+}
+
+// NewCodec returns the codec for format. The abi format requires a comma
+// separated list of Solidity types, e.g. "string,uint256".
+func NewCodec(format string, abiTypes string) (Codec, error) {
+	switch strings.ToLower(format) {
+	case "", "hex":
+		return HexCodec{}, nil
+	case "raw":
+		return RawCodec{}, nil
+	case "json":
+		return JSONCodec{}, nil
+	case "abi":
+		return NewABICodec(abiTypes)
+	default:
+		return nil, fmt.Errorf("unknown payload format %q (expected hex, raw, json or abi)", format)
+	}
+}
+
+// CodecFromEnv returns the codec configured for the AVS payload through
+// PERFORMER_PAYLOAD_FORMAT and PERFORMER_PAYLOAD_ABI, defaulting to hex.
+func CodecFromEnv() (Codec, error) {
+	return NewCodec(os.Getenv("PERFORMER_PAYLOAD_FORMAT"), os.Getenv("PERFORMER_PAYLOAD_ABI"))
+}
+
+// HexCodec reads and prints payloads as 0x-prefixed hex strings.
+type HexCodec struct{}
+
+func (HexCodec) Name() string { return "hex" }
+
+func (HexCodec) Encode(input []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(input))
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		s = "0x" + s
+	}
+	data, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex payload: %w", err)
+	}
+	return data, nil
+}
+
+func (HexCodec) Decode(data []byte) (any, error) {
+	return hexutil.Encode(data), nil
+}
+
+// RawCodec passes payload bytes through unchanged and prints them as a string.
+type RawCodec struct{}
+
+func (RawCodec) Name() string { return "raw" }
+
+func (RawCodec) Encode(input []byte) ([]byte, error) {
+	return input, nil
+}
+
+func (RawCodec) Decode(data []byte) (any, error) {
+	return string(data), nil
+}
+
+// JSONCodec treats payloads as JSON documents. Input is validated and
+// compacted so equal documents produce equal payload bytes.
+type JSONCodec struct{}
+
+func (JSONCodec) Name() string { return "json" }
+
+func (JSONCodec) Encode(input []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, bytes.TrimSpace(input)); err != nil {
+		return nil, fmt.Errorf("invalid JSON payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (JSONCodec) Decode(data []byte) (any, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("payload is not valid JSON")
+	}
+	return json.RawMessage(data), nil
+}
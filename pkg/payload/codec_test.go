@@ -0,0 +1,171 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Codecs(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		abiTypes string
+		input    string
+		decoded  string
+		wantErr  bool
+	}{
+		{name: "hex with prefix", format: "hex", input: "0xdeadbeef\n", decoded: `"0xdeadbeef"`},
+		{name: "hex without prefix", format: "hex", input: "deadbeef", decoded: `"0xdeadbeef"`},
+		{name: "invalid hex", format: "hex", input: "0xzz", wantErr: true},
+		{name: "raw", format: "raw", input: "hello", decoded: `"hello"`},
+		{name: "json is compacted", format: "json", input: "{ \"a\": 1 }\n", decoded: `{"a":1}`},
+		{name: "invalid json", format: "json", input: "{", wantErr: true},
+		{
+			name:     "abi",
+			format:   "abi",
+			abiTypes: "string message, uint256, address, bytes32, uint8[]",
+			input:    `["hi", "0x2a", "0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65", "0x` + string(bytes.Repeat([]byte("ab"), 32)) + `", [1, 2]]`,
+			decoded:  `["hi","42","0x15d34AAf54267DB7D7c367839AAf71A00a2C6A65","0x` + string(bytes.Repeat([]byte("ab"), 32)) + `",["1","2"]]`,
+		},
+		{name: "abi devkit signature", format: "abi", abiTypes: "(uint256,string)", input: `[5, "hello"]`, decoded: `["5","hello"]`},
+		{name: "abi wrong arity", format: "abi", abiTypes: "string", input: `["a", "b"]`, wantErr: true},
+		{name: "abi overflow", format: "abi", abiTypes: "uint8", input: `[256]`, wantErr: true},
+		{name: "abi negative unsigned", format: "abi", abiTypes: "uint256", input: `[-1]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := NewCodec(tt.format, tt.abiTypes)
+			if err != nil {
+				t.Fatalf("NewCodec failed: %v", err)
+			}
+
+			data, err := codec.Encode([]byte(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected Encode to fail, got %x", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+
+			decoded, err := codec.Decode(data)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			got, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("failed to marshal decoded value: %v", err)
+			}
+			if string(got) != tt.decoded {
+				t.Errorf("decoded value mismatch\n got: %s\nwant: %s", got, tt.decoded)
+			}
+
+			// Decoding and re-encoding must be lossless
+			if tt.format == "abi" {
+				again, err := codec.Encode(got)
+				if err != nil {
+					t.Fatalf("re-encoding decoded value failed: %v", err)
+				}
+				if !reflect.DeepEqual(again, data) {
+					t.Errorf("round trip mismatch")
+				}
+			}
+		})
+	}
+}
+
+func Test_ABIIntegerRanges(t *testing.T) {
+	tests := []struct {
+		abiType string
+		input   string
+		decoded string
+		wantErr bool
+	}{
+		{abiType: "int8", input: "127", decoded: "127"},
+		{abiType: "int8", input: "128", wantErr: true},
+		{abiType: "int8", input: "200", wantErr: true},
+		{abiType: "int8", input: "-128", decoded: "-128"},
+		{abiType: "int8", input: "-129", wantErr: true},
+		{abiType: "int24", input: "8388607", decoded: "8388607"},
+		{abiType: "int24", input: "8388608", wantErr: true},
+		{abiType: "int24", input: "-8388608", decoded: "-8388608"},
+		{abiType: "int256", input: `"-0x8000000000000000000000000000000000000000000000000000000000000000"`, decoded: "-57896044618658097711785492504343953926634992332820282019728792003956564819968"},
+		{abiType: "int256", input: `"0x8000000000000000000000000000000000000000000000000000000000000000"`, wantErr: true},
+		{abiType: "uint8", input: "255", decoded: "255"},
+		{abiType: "uint8", input: "256", wantErr: true},
+		{abiType: "uint256", input: `"0x` + strings.Repeat("ff", 32) + `"`, decoded: "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{abiType: "uint256", input: `"0x1` + strings.Repeat("00", 32) + `"`, wantErr: true},
+
+		// Decimal-looking values are decimal, and only 0x selects hex
+		{abiType: "uint256", input: `"010"`, decoded: "10"},
+		{abiType: "uint256", input: `"0x10"`, decoded: "16"},
+		{abiType: "uint256", input: `"1_000"`, wantErr: true},
+		{abiType: "uint256", input: `"0o10"`, wantErr: true},
+		{abiType: "uint256", input: `"0b10"`, wantErr: true},
+		{abiType: "int256", input: `"--1"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.abiType+" "+tt.input, func(t *testing.T) {
+			codec, err := NewABICodec(tt.abiType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := codec.Encode([]byte("[" + tt.input + "]"))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected Encode to fail, got %x", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			decoded, err := codec.Decode(data)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if got := decoded.([]any)[0]; got != tt.decoded {
+				t.Errorf("decoded %v, want %s", got, tt.decoded)
+			}
+		})
+	}
+}
+
+func Test_NewCodecErrors(t *testing.T) {
+	if _, err := NewCodec("yaml", ""); err == nil {
+		t.Error("expected unknown format to fail")
+	}
+	if _, err := NewCodec("abi", ""); err == nil {
+		t.Error("expected abi format without types to fail")
+	}
+	if _, err := NewCodec("abi", "notatype"); err == nil {
+		t.Error("expected invalid abi type to fail")
+	}
+}
+
+func Test_ABICodecSamples(t *testing.T) {
+	codec, err := NewABICodec("(uint256,string,address,bytes,bytes4,bool,int8[],uint16[2])")
+	if err != nil {
+		t.Fatalf("NewABICodec failed: %v", err)
+	}
+
+	samples, err := codec.Samples()
+	if err != nil {
+		t.Fatalf("Samples failed: %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+	for _, s := range samples {
+		if _, err := codec.Decode(s); err != nil {
+			t.Errorf("sample does not decode: %v", err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package recovery isolates tasks from each other by turning a panic inside a
+// task handler into an error, so a single bad task cannot take down the
+// Performer process.
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/metrics"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrTaskPanicked is matched (via errors.Is) by every error produced from a
+// recovered panic.
+var ErrTaskPanicked = errors.New("task panicked")
+
+// PanicError describes a recovered panic. It carries the gRPC Internal code so
+// the Executor treats it as a Performer failure rather than a bad request.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrTaskPanicked, e.Value)
+}
+
+func (e *PanicError) Is(target error) bool {
+	return target == ErrTaskPanicked
+}
+
+// GRPCStatus allows the gRPC server to map this error to codes.Internal. The
+// stack trace is kept out of the status message and only logged.
+func (e *PanicError) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, e.Error())
+}
+
+// Recover must be deferred directly by the function it protects:
+//
+//	func (tw *TaskWorker) HandleTask(t *performerV1.TaskRequest) (resp *performerV1.TaskResponse, err error) {
+//		defer recovery.Recover(tw.logger, t.GetTaskId(), &err)
+//		...
+//	}
+//
+// If a panic is in flight it is logged with its stack trace, counted in
+// metrics.TasksPanicked and stored in *errp as a *PanicError.
+func Recover(logger *zap.Logger, taskId []byte, errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	metrics.TasksPanicked.Inc(1)
+	logger.Error("Recovered from panic while processing task",
+		zap.Binary("taskId", taskId),
+		zap.Any("panic", r),
+		zap.ByteString("stack", stack),
+	)
+
+	*errp = &PanicError{Value: r, Stack: stack}
+}
@@ -0,0 +1,46 @@
+package recovery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/metrics"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Recover(t *testing.T) {
+	logger := zap.NewNop()
+
+	handle := func(shouldPanic bool) (err error) {
+		defer Recover(logger, []byte("task"), &err)
+		if shouldPanic {
+			var m map[string]int
+			m["boom"] = 1
+		}
+		return nil
+	}
+
+	before := metrics.TasksPanicked.Snapshot().Count()
+
+	if err := handle(false); err != nil {
+		t.Fatalf("expected no error without a panic, got %v", err)
+	}
+
+	err := handle(true)
+	if !errors.Is(err, ErrTaskPanicked) {
+		t.Fatalf("expected ErrTaskPanicked, got %v", err)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || len(panicErr.Stack) == 0 {
+		t.Errorf("expected a PanicError with a stack trace, got %#v", err)
+	}
+	if s, _ := status.FromError(err); s.Code() != codes.Internal {
+		t.Errorf("expected gRPC code Internal, got %v", s.Code())
+	}
+
+	if got := metrics.TasksPanicked.Snapshot().Count() - before; got != 1 {
+		t.Errorf("expected panic counter to increase by 1, got %d", got)
+	}
+}
@@ -0,0 +1,198 @@
+// Package replay re-runs historical tasks through a local Performer and
+// compares the results with the ones certified on chain. Tasks are read from
+// the TaskMailbox's TaskCreated and TaskVerified events, or from fixtures
+// exported by an earlier scan so that replays can run offline.
+//
+// Each replayed task sees the chain and the clock as they were when it was
+// created: L2 reads are pinned to the block of its TaskCreated event, L1 reads
+// to the last L1 block at or before that block's timestamp, and the clock is
+// frozen at that timestamp.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/consensus"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Task is a historical task as created and, if it was, verified on chain.
+type Task struct {
+	TaskHash              common.Hash    `json:"taskHash"`
+	Creator               common.Address `json:"creator"`
+	ExecutorOperatorSetId uint32         `json:"executorOperatorSetId"`
+
+	// Block is the L2 block of the TaskCreated event and Time its timestamp.
+	Block uint64 `json:"block"`
+	Time  int64  `json:"time"`
+	// L1Block is the last L1 block at or before Time, if it was looked up.
+	L1Block *uint64 `json:"l1Block,omitempty"`
+
+	Payload hexutil.Bytes `json:"payload"`
+
+	// Verified is set if a TaskVerified event was found for the task, in
+	// which case Result is the certified result.
+	Verified bool          `json:"verified"`
+	Result   hexutil.Bytes `json:"result,omitempty"`
+}
+
+// Fixtures are the tasks found by a scan.
+type Fixtures struct {
+	Mailbox   common.Address `json:"mailbox"`
+	Avs       common.Address `json:"avs"`
+	FromBlock uint64         `json:"fromBlock"`
+	ToBlock   uint64         `json:"toBlock"`
+	Tasks     []Task         `json:"tasks"`
+}
+
+// LoadFixtures reads fixtures written by Save.
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid replay fixtures %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Save writes the fixtures to path as JSON.
+func (f *Fixtures) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Status is the outcome of replaying one task.
+type Status string
+
+const (
+	// StatusMatch means the local result equals the certified one.
+	StatusMatch Status = "match"
+	// StatusDiverged means the local result differs from the certified one.
+	StatusDiverged Status = "diverged"
+	// StatusFailed means the task failed locally although it was certified.
+	StatusFailed Status = "failed"
+	// StatusUnverified means the task has no certified result to compare
+	// with. It was still run, so Result or Error shows what it produces now.
+	StatusUnverified Status = "unverified"
+)
+
+// Outcome is the result of replaying one task.
+type Outcome struct {
+	TaskHash        common.Hash   `json:"taskHash"`
+	Block           uint64        `json:"block"`
+	Status          Status        `json:"status"`
+	CertifiedResult hexutil.Bytes `json:"certifiedResult,omitempty"`
+	Result          hexutil.Bytes `json:"result,omitempty"`
+	Error           string        `json:"error,omitempty"`
+	// L1Unpinned is set if L1 reads could not be pinned because the task has
+	// no L1 block.
+	L1Unpinned bool `json:"l1Unpinned,omitempty"`
+}
+
+// Report summarizes a replay.
+type Report struct {
+	Tasks      int       `json:"tasks"`
+	Matched    int       `json:"matched"`
+	Diverged   int       `json:"diverged"`
+	Failed     int       `json:"failed"`
+	Unverified int       `json:"unverified"`
+	Outcomes   []Outcome `json:"outcomes"`
+}
+
+// Changed reports whether any certified task now produces a different result
+// or fails.
+func (r *Report) Changed() bool {
+	return r.Diverged > 0 || r.Failed > 0
+}
+
+// Factory builds the Performer task is replayed on. The Performer must use
+// clock as its time source and send its L1 and L2 RPC traffic through l1 and
+// l2; l1 is nil if L1 reads can't be pinned. If the Performer implements
+// io.Closer, it is closed once its task has been replayed.
+type Factory func(task Task, clock func() time.Time, l1 http.RoundTripper, l2 http.RoundTripper) (consensus.Performer, error)
+
+// Replayer re-runs tasks on Performers built by Factory.
+type Replayer struct {
+	Factory Factory
+}
+
+// Run replays every task, each on its own Performer, and compares the results
+// with the certified ones.
+func (r *Replayer) Run(tasks []Task) (*Report, error) {
+	report := &Report{Tasks: len(tasks), Outcomes: make([]Outcome, 0, len(tasks))}
+	for _, task := range tasks {
+		o, err := r.replay(task)
+		if err != nil {
+			return nil, err
+		}
+		switch o.Status {
+		case StatusMatch:
+			report.Matched++
+		case StatusDiverged:
+			report.Diverged++
+		case StatusFailed:
+			report.Failed++
+		case StatusUnverified:
+			report.Unverified++
+		}
+		report.Outcomes = append(report.Outcomes, o)
+	}
+	return report, nil
+}
+
+func (r *Replayer) replay(task Task) (Outcome, error) {
+	created := time.Unix(task.Time, 0)
+	var l1 http.RoundTripper
+	if task.L1Block != nil {
+		l1 = &consensus.PinnedTransport{Block: *task.L1Block}
+	}
+	l2 := &consensus.PinnedTransport{Block: task.Block}
+
+	p, err := r.Factory(task, func() time.Time { return created }, l1, l2)
+	if err != nil {
+		return Outcome{}, fmt.Errorf("failed to create performer for task %s: %w", task.TaskHash, err)
+	}
+	if c, ok := p.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	o := Outcome{TaskHash: task.TaskHash, Block: task.Block, L1Unpinned: l1 == nil}
+	if task.Verified {
+		o.CertifiedResult = task.Result
+	}
+
+	req := &performerV1.TaskRequest{TaskId: task.TaskHash.Bytes(), Payload: task.Payload}
+	if err := p.ValidateTask(req); err != nil {
+		o.Error = fmt.Sprintf("validation failed: %v", err)
+	} else if resp, err := p.HandleTask(req); err != nil {
+		o.Error = fmt.Sprintf("handling failed: %v", err)
+	} else {
+		o.Result = resp.Result
+	}
+
+	switch {
+	case !task.Verified:
+		o.Status = StatusUnverified
+	case o.Error != "":
+		o.Status = StatusFailed
+	case bytes.Equal(o.Result, task.Result):
+		o.Status = StatusMatch
+	default:
+		o.Status = StatusDiverged
+	}
+	return o, nil
+}
@@ -0,0 +1,195 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/consensus"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	performerV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/hourglass/v1/performer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var avs = common.HexToAddress("0xa5")
+
+// fakeEvents serves events from memory and records the queried ranges.
+type fakeEvents struct {
+	created  []mailbox.TaskCreatedEvent
+	verified []mailbox.TaskVerifiedEvent
+	ranges   [][2]uint64
+}
+
+func (f *fakeEvents) FilterTaskCreated(_ context.Context, from uint64, to uint64, _ common.Address) ([]mailbox.TaskCreatedEvent, error) {
+	f.ranges = append(f.ranges, [2]uint64{from, to})
+	var out []mailbox.TaskCreatedEvent
+	for _, ev := range f.created {
+		if ev.Raw.BlockNumber >= from && ev.Raw.BlockNumber <= to {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEvents) FilterTaskVerified(_ context.Context, from uint64, to uint64, _ common.Address) ([]mailbox.TaskVerifiedEvent, error) {
+	var out []mailbox.TaskVerifiedEvent
+	for _, ev := range f.verified {
+		if ev.Raw.BlockNumber >= from && ev.Raw.BlockNumber <= to {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+// fakeHeaders is a chain whose block n has timestamp start + n*step.
+type fakeHeaders struct {
+	start, step, head uint64
+}
+
+func (f fakeHeaders) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	n := f.head
+	if number != nil {
+		n = number.Uint64()
+	}
+	if n > f.head {
+		return nil, errors.New("block not found")
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(n), Time: f.start + n*f.step}, nil
+}
+
+func Test_Scan(t *testing.T) {
+	events := &fakeEvents{
+		created: []mailbox.TaskCreatedEvent{
+			{TaskHash: common.Hash{2}, Payload: []byte("b"), Raw: types.Log{BlockNumber: 25}},
+			{TaskHash: common.Hash{1}, Payload: []byte("a"), Raw: types.Log{BlockNumber: 12}},
+		},
+		verified: []mailbox.TaskVerifiedEvent{
+			{TaskHash: common.Hash{1}, Result: []byte("ok"), Raw: types.Log{BlockNumber: 14}},
+		},
+	}
+	scanner := &Scanner{
+		Events:    events,
+		L2Headers: fakeHeaders{start: 1000, step: 2, head: 100},
+		L1Headers: fakeHeaders{start: 990, step: 12, head: 50},
+		ChunkSize: 10,
+	}
+
+	tasks, err := scanner.Scan(context.Background(), avs, 5, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][2]uint64{{5, 14}, {15, 24}, {25, 30}}; len(events.ranges) != len(want) || events.ranges[2] != want[2] {
+		t.Errorf("queried ranges %v, want %v", events.ranges, want)
+	}
+	if len(tasks) != 2 || tasks[0].TaskHash != (common.Hash{1}) {
+		t.Fatalf("expected tasks in creation order, got %+v", tasks)
+	}
+	if !tasks[0].Verified || string(tasks[0].Result) != "ok" || tasks[1].Verified {
+		t.Errorf("unexpected verification state %+v", tasks)
+	}
+	// Block 12 is at 1024; the last L1 block at or before it is 2 (1014)
+	if tasks[0].Time != 1024 || tasks[0].L1Block == nil || *tasks[0].L1Block != 2 {
+		t.Errorf("unexpected time or L1 block %+v", tasks[0])
+	}
+
+	if _, err := scanner.Scan(context.Background(), avs, 30, 5); err == nil {
+		t.Error("expected an error for a reversed range")
+	}
+}
+
+func Test_BlockAt(t *testing.T) {
+	headers := fakeHeaders{start: 100, step: 10, head: 20}
+	for ts, want := range map[uint64]uint64{100: 0, 109: 0, 110: 1, 255: 15, 300: 20, 1000: 20} {
+		got, err := BlockAt(context.Background(), headers, ts)
+		if err != nil || got != want {
+			t.Errorf("BlockAt(%d) = %d, %v; want %d", ts, got, err, want)
+		}
+	}
+	if _, err := BlockAt(context.Background(), headers, 99); err == nil {
+		t.Error("expected an error before the first block")
+	}
+}
+
+// echoPerformer returns the payload as the result, and fails on "fail".
+type echoPerformer struct {
+	closed *int
+}
+
+func (echoPerformer) ValidateTask(*performerV1.TaskRequest) error { return nil }
+
+func (p echoPerformer) Close() error {
+	*p.closed++
+	return nil
+}
+
+func (echoPerformer) HandleTask(t *performerV1.TaskRequest) (*performerV1.TaskResponse, error) {
+	if string(t.Payload) == "fail" {
+		return nil, errors.New("boom")
+	}
+	return &performerV1.TaskResponse{TaskId: t.TaskId, Result: t.Payload}, nil
+}
+
+func Test_Replay(t *testing.T) {
+	l1Block := uint64(7)
+	tasks := []Task{
+		{TaskHash: common.Hash{1}, Block: 10, Time: 1000, L1Block: &l1Block, Payload: []byte("same"), Verified: true, Result: []byte("same")},
+		{TaskHash: common.Hash{2}, Block: 11, Payload: []byte("new"), Verified: true, Result: []byte("old")},
+		{TaskHash: common.Hash{3}, Block: 12, Payload: []byte("fail"), Verified: true, Result: []byte("x")},
+		{TaskHash: common.Hash{4}, Block: 13, Payload: []byte("pending")},
+	}
+
+	var clocks []time.Time
+	var pins []uint64
+	var closed int
+	r := &Replayer{Factory: func(task Task, clock func() time.Time, l1 http.RoundTripper, l2 http.RoundTripper) (consensus.Performer, error) {
+		clocks = append(clocks, clock())
+		pins = append(pins, l2.(*consensus.PinnedTransport).Block)
+		if task.L1Block != nil && l1.(*consensus.PinnedTransport).Block != *task.L1Block {
+			t.Errorf("L1 reads of %s are not pinned to block %d", task.TaskHash, *task.L1Block)
+		}
+		return echoPerformer{closed: &closed}, nil
+	}}
+
+	report, err := r.Run(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Status{StatusMatch, StatusDiverged, StatusFailed, StatusUnverified}
+	for i, o := range report.Outcomes {
+		if o.Status != want[i] {
+			t.Errorf("task %d: status %s, want %s", i, o.Status, want[i])
+		}
+	}
+	if !report.Changed() || report.Matched != 1 || report.Diverged != 1 || report.Failed != 1 || report.Unverified != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if report.Outcomes[0].L1Unpinned || !report.Outcomes[1].L1Unpinned {
+		t.Errorf("unexpected L1 pinning %+v", report.Outcomes)
+	}
+	if !clocks[0].Equal(time.Unix(1000, 0)) || pins[3] != 13 {
+		t.Errorf("unexpected clocks %v or pinned blocks %v", clocks, pins)
+	}
+	if closed != len(tasks) {
+		t.Errorf("closed %d performers, want %d", closed, len(tasks))
+	}
+}
+
+func Test_FixturesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	want := &Fixtures{Avs: avs, FromBlock: 1, ToBlock: 2, Tasks: []Task{{TaskHash: common.Hash{1}, Payload: []byte{1, 2}, Verified: true, Result: []byte{3}}}}
+	if err := want.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Avs != avs || len(got.Tasks) != 1 || string(got.Tasks[0].Result) != string([]byte{3}) {
+		t.Errorf("unexpected fixtures %+v", got)
+	}
+}
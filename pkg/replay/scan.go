@@ -0,0 +1,158 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultChunkSize is the number of blocks read per eth_getLogs call, which
+// most RPC providers accept.
+const DefaultChunkSize = 2000
+
+// EventFilterer reads TaskMailbox events. It is implemented by
+// *mailbox.TaskMailbox.
+type EventFilterer interface {
+	FilterTaskCreated(ctx context.Context, from uint64, to uint64, avs common.Address) ([]mailbox.TaskCreatedEvent, error)
+	FilterTaskVerified(ctx context.Context, from uint64, to uint64, avs common.Address) ([]mailbox.TaskVerifiedEvent, error)
+}
+
+// HeaderReader reads block headers. It is implemented by *ethclient.Client.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Scanner finds an AVS's tasks in a range of L2 blocks.
+type Scanner struct {
+	Events    EventFilterer
+	L2Headers HeaderReader
+	// L1Headers, if set, is used to find the L1 block each task was created
+	// at, so that replays can pin L1 reads.
+	L1Headers HeaderReader
+	// ChunkSize is the number of blocks per event query; zero uses
+	// DefaultChunkSize.
+	ChunkSize uint64
+}
+
+// Scan returns the tasks of avs created between blocks from and to,
+// inclusive, in the order they were created. Tasks verified after to are
+// reported as unverified.
+func (s *Scanner) Scan(ctx context.Context, avs common.Address, from uint64, to uint64) ([]Task, error) {
+	if from > to {
+		return nil, fmt.Errorf("from block %d is after to block %d", from, to)
+	}
+	chunk := s.ChunkSize
+	if chunk == 0 {
+		chunk = DefaultChunkSize
+	}
+
+	var created []mailbox.TaskCreatedEvent
+	verified := make(map[common.Hash]mailbox.TaskVerifiedEvent)
+	for start := from; ; start += chunk {
+		end := min(start+chunk-1, to)
+		c, err := s.Events.FilterTaskCreated(ctx, start, end, avs)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, c...)
+		v, err := s.Events.FilterTaskVerified(ctx, start, end, avs)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range v {
+			verified[ev.TaskHash] = ev
+		}
+		if end == to {
+			break
+		}
+	}
+	sort.SliceStable(created, func(i, j int) bool {
+		if created[i].Raw.BlockNumber != created[j].Raw.BlockNumber {
+			return created[i].Raw.BlockNumber < created[j].Raw.BlockNumber
+		}
+		return created[i].Raw.Index < created[j].Raw.Index
+	})
+
+	times := make(map[uint64]uint64)
+	l1Blocks := make(map[uint64]uint64)
+	tasks := make([]Task, 0, len(created))
+	for _, ev := range created {
+		block := ev.Raw.BlockNumber
+		ts, ok := times[block]
+		if !ok {
+			header, err := s.L2Headers.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read L2 block %d: %w", block, err)
+			}
+			ts = header.Time
+			times[block] = ts
+		}
+
+		task := Task{
+			TaskHash:              ev.TaskHash,
+			Creator:               ev.Creator,
+			ExecutorOperatorSetId: ev.ExecutorOperatorSetId,
+			Block:                 block,
+			Time:                  int64(ts),
+			Payload:               ev.Payload,
+		}
+		if s.L1Headers != nil {
+			l1Block, ok := l1Blocks[ts]
+			if !ok {
+				var err error
+				if l1Block, err = BlockAt(ctx, s.L1Headers, ts); err != nil {
+					return nil, fmt.Errorf("failed to find the L1 block of task %s: %w", ev.TaskHash, err)
+				}
+				l1Blocks[ts] = l1Block
+			}
+			task.L1Block = &l1Block
+		}
+		if v, ok := verified[ev.TaskHash]; ok {
+			task.Verified = true
+			task.Result = v.Result
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// BlockAt returns the last block whose timestamp is at or before ts.
+func BlockAt(ctx context.Context, headers HeaderReader, ts uint64) (uint64, error) {
+	head, err := headers.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	if head.Time <= ts {
+		return head.Number.Uint64(), nil
+	}
+
+	// The first block is at or before ts unless ts predates the chain
+	lo, hi := uint64(0), head.Number.Uint64()
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		header, err := headers.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, err
+		}
+		if header.Time <= ts {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	if lo == 0 {
+		genesis, err := headers.HeaderByNumber(ctx, big.NewInt(0))
+		if err != nil {
+			return 0, err
+		}
+		if genesis.Time > ts {
+			return 0, fmt.Errorf("timestamp %d is before the first block", ts)
+		}
+	}
+	return lo, nil
+}
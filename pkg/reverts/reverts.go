@@ -0,0 +1,264 @@
+// Package reverts decodes the revert data of failed contract calls into typed
+// errors, using the custom errors in the ABIs of the contracts the Performer
+// talks to.
+package reverts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrReverted is matched (via errors.Is) by every *RevertError.
+var ErrReverted = errors.New("execution reverted")
+
+var (
+	errorSelector = [4]byte(crypto.Keccak256([]byte("Error(string)"))[:4])
+	panicSelector = [4]byte(crypto.Keccak256([]byte("Panic(uint256)"))[:4])
+)
+
+// Arg is a decoded argument of a custom error.
+type Arg struct {
+	Name  string
+	Value any
+	// Display is Value as shown in error messages, with enums by name.
+	Display string
+}
+
+// RevertError is a decoded revert. Name is the custom error, or "Error" and
+// "Panic" for require messages and panics; it is empty if the error is not in
+// any registered ABI.
+type RevertError struct {
+	// Contract is the contract whose ABI defines the error, if any.
+	Contract string
+	Name     string
+	Args     []Arg
+	Data     []byte
+
+	err error
+}
+
+// Reason describes the revert, e.g.
+// "TaskMailbox.InvalidTaskStatus(expected: CREATED, actual: VERIFIED)".
+func (e *RevertError) Reason() string {
+	switch e.Name {
+	case "":
+		if len(e.Data) == 0 {
+			return "no revert data"
+		}
+		return fmt.Sprintf("unknown error %s", hexutil.Encode(e.Data))
+	case "Error", "Panic":
+		return fmt.Sprintf("%s(%s)", e.Name, e.Args[0].Display)
+	}
+
+	args := make([]string, len(e.Args))
+	for i, a := range e.Args {
+		args[i] = a.Display
+		if a.Name != "" {
+			args[i] = a.Name + ": " + a.Display
+		}
+	}
+	name := e.Name
+	if e.Contract != "" {
+		name = e.Contract + "." + name
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+}
+
+func (e *RevertError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("%s: %s", ErrReverted, e.Reason())
+	}
+	return fmt.Sprintf("%s: %s", e.err, e.Reason())
+}
+
+func (e *RevertError) Is(target error) bool {
+	return target == ErrReverted
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.err
+}
+
+// Arg returns the argument called name.
+func (e *RevertError) Arg(name string) (Arg, bool) {
+	for _, a := range e.Args {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Arg{}, false
+}
+
+// Is reports whether err is a revert with the custom error called name.
+func Is(err error, name string) bool {
+	var revert *RevertError
+	return errors.As(err, &revert) && revert.Name == name
+}
+
+type customError struct {
+	contract string
+	abi      abi.Error
+}
+
+// Decoder maps revert data to the custom errors of registered ABIs.
+type Decoder struct {
+	mu     sync.RWMutex
+	errors map[[4]byte]customError
+	enums  map[string]func(uint8) string
+}
+
+// NewDecoder creates a Decoder without any custom errors. Error(string) and
+// Panic(uint256) reverts are always decoded.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		errors: make(map[[4]byte]customError),
+		enums:  make(map[string]func(uint8) string),
+	}
+}
+
+// Register adds the custom errors in contract's ABI. An error already
+// registered by another contract with the same signature is kept.
+func (d *Decoder) Register(contract string, parsed abi.ABI) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, e := range parsed.Errors {
+		selector := [4]byte(e.ID[:4])
+		if _, ok := d.errors[selector]; !ok {
+			d.errors[selector] = customError{contract: contract, abi: e}
+		}
+	}
+}
+
+// RegisterEnum shows the argument arg of the custom error called errorName by
+// name rather than number.
+func (d *Decoder) RegisterEnum(errorName string, arg string, names func(uint8) string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.enums[errorName+"."+arg] = names
+}
+
+// DecodeData decodes revert data.
+func (d *Decoder) DecodeData(data []byte) *RevertError {
+	revert := &RevertError{Data: data}
+	if len(data) < 4 {
+		return revert
+	}
+	selector := [4]byte(data[:4])
+
+	if selector == errorSelector || selector == panicSelector {
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return revert
+		}
+		revert.Name = "Error"
+		if selector == panicSelector {
+			revert.Name = "Panic"
+		}
+		revert.Args = []Arg{{Value: reason, Display: fmt.Sprintf("%q", reason)}}
+		return revert
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	custom, ok := d.errors[selector]
+	if !ok {
+		return revert
+	}
+	values, err := custom.abi.Inputs.Unpack(data[4:])
+	if err != nil {
+		return revert
+	}
+	revert.Contract = custom.contract
+	revert.Name = custom.abi.Name
+	for i, input := range custom.abi.Inputs {
+		arg := Arg{Name: input.Name, Value: values[i], Display: display(values[i])}
+		if names, ok := d.enums[custom.abi.Name+"."+input.Name]; ok {
+			if v, ok := values[i].(uint8); ok {
+				arg.Display = names(v)
+			}
+		}
+		revert.Args = append(revert.Args, arg)
+	}
+	return revert
+}
+
+// Decode returns err wrapped in a *RevertError if it carries revert data, and
+// err unchanged otherwise.
+func (d *Decoder) Decode(err error) error {
+	if err == nil {
+		return nil
+	}
+	var revert *RevertError
+	if errors.As(err, &revert) {
+		return err
+	}
+	data, ok := revertData(err)
+	if !ok {
+		return err
+	}
+	revert = d.DecodeData(data)
+	revert.err = err
+	return revert
+}
+
+// revertData extracts the revert data an RPC error carries.
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	switch data := dataErr.ErrorData().(type) {
+	case string:
+		b, err := hexutil.Decode(data)
+		return b, err == nil
+	case []byte:
+		return data, true
+	default:
+		return nil, false
+	}
+}
+
+func display(v any) string {
+	switch v := v.(type) {
+	case common.Address:
+		return v.Hex()
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case []byte:
+		return hexutil.Encode(v)
+	case *big.Int:
+		return v.String()
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Default is the decoder used by Decode. Binding packages register their
+// contracts' errors with it when they are initialized.
+var Default = NewDecoder()
+
+// Register adds contract's custom errors to the Default decoder.
+func Register(contract string, parsed abi.ABI) {
+	Default.Register(contract, parsed)
+}
+
+// RegisterEnum registers an enum argument with the Default decoder.
+func RegisterEnum(errorName string, arg string, names func(uint8) string) {
+	Default.RegisterEnum(errorName, arg, names)
+}
+
+// Decode decodes err's revert data with the Default decoder.
+func Decode(err error) error {
+	return Default.Decode(err)
+}
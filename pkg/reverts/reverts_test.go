@@ -0,0 +1,107 @@
+package reverts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const testABI = `[
+	{"type":"error","name":"InvalidTaskStatus","inputs":[{"name":"expected","type":"uint8"},{"name":"actual","type":"uint8"}]},
+	{"type":"error","name":"PayloadIsEmpty","inputs":[]}
+]`
+
+// dataError is an RPC error carrying revert data, as returned by eth_call and
+// eth_estimateGas.
+type dataError struct {
+	data string
+}
+
+func (e *dataError) Error() string          { return "execution reverted" }
+func (e *dataError) ErrorData() interface{} { return e.data }
+
+func testDecoder(t *testing.T) (*Decoder, abi.ABI) {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDecoder()
+	d.Register("TaskMailbox", parsed)
+	d.RegisterEnum("InvalidTaskStatus", "expected", func(v uint8) string { return []string{"NONE", "CREATED"}[v] })
+	return d, parsed
+}
+
+func revertWith(t *testing.T, parsed abi.ABI, name string, args ...any) error {
+	t.Helper()
+	e := parsed.Errors[name]
+	packed, err := e.Inputs.Pack(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &dataError{data: hexutil.Encode(append(e.ID[:4], packed...))}
+}
+
+func Test_DecodeCustomError(t *testing.T) {
+	d, parsed := testDecoder(t)
+
+	err := fmt.Errorf("submitResult failed: %w", revertWith(t, parsed, "InvalidTaskStatus", uint8(1), uint8(0)))
+	decoded := d.Decode(err)
+
+	var revert *RevertError
+	if !errors.As(decoded, &revert) || !errors.Is(decoded, ErrReverted) {
+		t.Fatalf("expected a RevertError, got %v", decoded)
+	}
+	want := "submitResult failed: execution reverted: TaskMailbox.InvalidTaskStatus(expected: CREATED, actual: 0)"
+	if decoded.Error() != want {
+		t.Errorf("Error() = %q, want %q", decoded, want)
+	}
+	if arg, ok := revert.Arg("actual"); !ok || arg.Value != uint8(0) {
+		t.Errorf("actual = %+v", arg)
+	}
+	var rpcErr *dataError
+	if !errors.As(decoded, &rpcErr) {
+		t.Error("expected the RPC error to stay in the chain")
+	}
+
+	// Decoding twice doesn't wrap again
+	if again := d.Decode(decoded); again != decoded {
+		t.Errorf("Decode was not idempotent: %v", again)
+	}
+	if !Is(decoded, "InvalidTaskStatus") || Is(decoded, "PayloadIsEmpty") {
+		t.Error("Is matched the wrong error")
+	}
+}
+
+func Test_DecodeBuiltinErrors(t *testing.T) {
+	d := NewDecoder()
+	stringType, _ := abi.NewType("string", "", nil)
+	uintType, _ := abi.NewType("uint256", "", nil)
+
+	reason, _ := abi.Arguments{{Type: stringType}}.Pack("not the owner")
+	if got := d.DecodeData(append(hexutil.MustDecode("0x08c379a0"), reason...)).Reason(); got != `Error("not the owner")` {
+		t.Errorf("Error(string) decoded as %q", got)
+	}
+	code, _ := abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x11))
+	if got := d.DecodeData(append(hexutil.MustDecode("0x4e487b71"), code...)).Reason(); !strings.Contains(got, "overflow") {
+		t.Errorf("Panic(uint256) decoded as %q", got)
+	}
+	if got := d.DecodeData(hexutil.MustDecode("0xdeadbeef")).Reason(); got != "unknown error 0xdeadbeef" {
+		t.Errorf("unknown selector decoded as %q", got)
+	}
+}
+
+func Test_DecodeLeavesOtherErrors(t *testing.T) {
+	err := errors.New("connection refused")
+	if got := NewDecoder().Decode(err); got != err {
+		t.Errorf("Decode changed a non-revert error: %v", got)
+	}
+	if NewDecoder().Decode(nil) != nil {
+		t.Error("Decode(nil) != nil")
+	}
+}
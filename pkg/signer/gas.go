@@ -0,0 +1,124 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// FeeSource provides the chain data needed to price EIP-1559 transactions.
+// ethclient.Client implements it.
+type FeeSource interface {
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// defaultBaseFeeMultiplier leaves room for the base fee to rise for several
+// consecutive full blocks before the transaction stops being includable.
+const defaultBaseFeeMultiplier = 2
+
+// GasPolicy prices EIP-1559 transactions. The zero value uses the node's
+// suggested tip and a fee cap of twice the latest base fee plus the tip.
+type GasPolicy struct {
+	// TipCap fixes the priority fee. If nil, the node's suggestion is used.
+	TipCap *big.Int
+
+	// MaxTipCap caps a suggested priority fee. Ignored if nil.
+	MaxTipCap *big.Int
+
+	// BaseFeeMultiplier scales the latest base fee when computing the fee cap.
+	BaseFeeMultiplier uint64
+
+	// MaxFeeCap refuses to send if the computed fee cap exceeds it. Ignored
+	// if nil.
+	MaxFeeCap *big.Int
+}
+
+// GasPolicyFromEnv reads the gas policy from the environment:
+//
+//	GAS_TIP_CAP_GWEI          fixed priority fee in gwei
+//	GAS_MAX_TIP_CAP_GWEI      upper bound on a suggested priority fee in gwei
+//	GAS_BASE_FEE_MULTIPLIER   base fee multiplier for the fee cap (default 2)
+//	GAS_MAX_FEE_CAP_GWEI      refuse to send above this fee cap in gwei
+func GasPolicyFromEnv() (GasPolicy, error) {
+	var policy GasPolicy
+	var err error
+
+	if policy.TipCap, err = envGwei("GAS_TIP_CAP_GWEI"); err != nil {
+		return policy, err
+	}
+	if policy.MaxTipCap, err = envGwei("GAS_MAX_TIP_CAP_GWEI"); err != nil {
+		return policy, err
+	}
+	if policy.MaxFeeCap, err = envGwei("GAS_MAX_FEE_CAP_GWEI"); err != nil {
+		return policy, err
+	}
+	if v := os.Getenv("GAS_BASE_FEE_MULTIPLIER"); v != "" {
+		if policy.BaseFeeMultiplier, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return policy, fmt.Errorf("invalid GAS_BASE_FEE_MULTIPLIER %q: %w", v, err)
+		}
+	}
+	return policy, nil
+}
+
+// ParseGwei parses a decimal gwei amount such as "1.5" into wei.
+func ParseGwei(s string) (*big.Int, error) {
+	gwei, ok := new(big.Float).SetPrec(256).SetString(s)
+	if !ok || gwei.Sign() < 0 {
+		return nil, fmt.Errorf("invalid gwei amount %q", s)
+	}
+	wei, _ := gwei.Mul(gwei, big.NewFloat(params.GWei)).Int(nil)
+	return wei, nil
+}
+
+func envGwei(name string) (*big.Int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil, nil
+	}
+	wei, err := ParseGwei(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return wei, nil
+}
+
+// Fees returns the priority fee and fee cap for a transaction sent now.
+func (p GasPolicy) Fees(ctx context.Context, source FeeSource) (tipCap *big.Int, feeCap *big.Int, err error) {
+	if p.TipCap != nil {
+		tipCap = new(big.Int).Set(p.TipCap)
+	} else {
+		tipCap, err = source.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+		}
+		if p.MaxTipCap != nil && tipCap.Cmp(p.MaxTipCap) > 0 {
+			tipCap = new(big.Int).Set(p.MaxTipCap)
+		}
+	}
+
+	head, err := source.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch latest header: %w", err)
+	}
+	if head.BaseFee == nil {
+		return nil, nil, fmt.Errorf("chain does not support EIP-1559 (latest block has no base fee)")
+	}
+
+	multiplier := p.BaseFeeMultiplier
+	if multiplier == 0 {
+		multiplier = defaultBaseFeeMultiplier
+	}
+	feeCap = new(big.Int).Mul(head.BaseFee, new(big.Int).SetUint64(multiplier))
+	feeCap.Add(feeCap, tipCap)
+
+	if p.MaxFeeCap != nil && feeCap.Cmp(p.MaxFeeCap) > 0 {
+		return nil, nil, fmt.Errorf("fee cap %s exceeds configured maximum %s", feeCap, p.MaxFeeCap)
+	}
+	return tipCap, feeCap, nil
+}
@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceSource returns the next nonce for an account, counting pending
+// transactions. ethclient.Client implements it.
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out sequential nonces so several transactions can be
+// sent without waiting for each one to reach the pending pool. The first
+// nonce for an account is fetched from the chain; later ones are counted
+// locally until Reset is called.
+type NonceManager struct {
+	source NonceSource
+
+	mu   sync.Mutex
+	next map[common.Address]uint64
+}
+
+func NewNonceManager(source NonceSource) *NonceManager {
+	return &NonceManager{
+		source: source,
+		next:   make(map[common.Address]uint64),
+	}
+}
+
+// Next reserves and returns the next nonce for account.
+func (m *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce, ok := m.next[account]
+	if !ok {
+		pending, err := m.source.PendingNonceAt(ctx, account)
+		if err != nil {
+			return 0, err
+		}
+		nonce = pending
+	}
+	m.next[account] = nonce + 1
+	return nonce, nil
+}
+
+// Reset forgets the locally tracked nonce for account, so the next call to
+// Next re-reads it from the chain. Call it when a transaction using a
+// reserved nonce was not sent.
+func (m *NonceManager) Reset(account common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.next, account)
+}
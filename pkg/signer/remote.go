@@ -0,0 +1,230 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RemoteSigner signs transactions through a remote signer's
+// eth_signTransaction JSON-RPC method, as exposed by web3signer in eth1 mode.
+// The private key never leaves the remote signer.
+type RemoteSigner struct {
+	url        string
+	address    common.Address
+	httpClient *http.Client
+	nextId     atomic.Int64
+}
+
+func NewRemoteSigner(url string, address common.Address) *RemoteSigner {
+	return &RemoteSigner{
+		url:        url,
+		address:    address,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// signTxArgs are the eth_signTransaction parameters.
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainId              *hexutil.Big    `json:"chainId"`
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	Id      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type jsonRPCResponse struct {
+	Id     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *jsonRPCError   `json:"error"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if tx.Type() != types.DynamicFeeTxType {
+		return nil, fmt.Errorf("remote signer only signs EIP-1559 transactions, got type %d", tx.Type())
+	}
+
+	args := signTxArgs{
+		From:                 s.address,
+		To:                   tx.To(),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Data:                 tx.Data(),
+		ChainId:              (*hexutil.Big)(chainID),
+	}
+
+	var raw hexutil.Bytes
+	if err := s.call(ctx, "eth_signTransaction", []any{args}, &raw); err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid transaction: %w", err)
+	}
+
+	// Never trust the remote side to have signed what we asked for
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an unverifiable signature: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, expected %s", sender, s.address)
+	}
+	if signed.ChainId().Cmp(chainID) != 0 || !sameUnsigned(signed, tx) {
+		return nil, fmt.Errorf("remote signer returned a transaction that differs from the request")
+	}
+	return signed, nil
+}
+
+// sameUnsigned reports whether two transactions carry the same payload,
+// ignoring signatures.
+func sameUnsigned(a *types.Transaction, b *types.Transaction) bool {
+	return a.Nonce() == b.Nonce() &&
+		a.Gas() == b.Gas() &&
+		a.GasTipCap().Cmp(b.GasTipCap()) == 0 &&
+		a.GasFeeCap().Cmp(b.GasFeeCap()) == 0 &&
+		a.Value().Cmp(b.Value()) == 0 &&
+		bytes.Equal(a.Data(), b.Data()) &&
+		((a.To() == nil && b.To() == nil) || (a.To() != nil && b.To() != nil && *a.To() == *b.To()))
+}
+
+func (s *RemoteSigner) call(ctx context.Context, method string, params []any, result any) error {
+	body, err := json.Marshal(jsonRPCRequest{JSONRPC: "2.0", Id: s.nextId.Add(1), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer returned HTTP %d", resp.StatusCode)
+	}
+
+	var rpcResp jsonRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("invalid remote signer response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("remote signer error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	return json.Unmarshal(rpcResp.Result, result)
+}
+
+// NewRemoteSignerHandler serves eth_accounts and eth_signTransaction for s,
+// standing in for web3signer on a local devnet or in tests.
+func NewRemoteSignerHandler(s Signer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     int64             `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON-RPC request", http.StatusBadRequest)
+			return
+		}
+
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.Id}
+		result, err := serveSignerMethod(r.Context(), s, req.Method, req.Params)
+		if err != nil {
+			resp["error"] = jsonRPCError{Code: -32000, Message: err.Error()}
+		} else {
+			resp["result"] = result
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+func serveSignerMethod(ctx context.Context, s Signer, method string, params []json.RawMessage) (any, error) {
+	switch method {
+	case "eth_accounts":
+		return []common.Address{s.Address()}, nil
+
+	case "eth_signTransaction":
+		if len(params) != 1 {
+			return nil, fmt.Errorf("eth_signTransaction expects 1 parameter")
+		}
+		var args signTxArgs
+		if err := json.Unmarshal(params[0], &args); err != nil {
+			return nil, err
+		}
+		if args.From != s.Address() {
+			return nil, fmt.Errorf("unknown account %s", args.From)
+		}
+		if args.ChainId == nil || args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil {
+			return nil, fmt.Errorf("chainId, maxFeePerGas and maxPriorityFeePerGas are required")
+		}
+		value := new(big.Int)
+		if args.Value != nil {
+			value = args.Value.ToInt()
+		}
+
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   args.ChainId.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     value,
+			Data:      args.Data,
+		})
+		signed, err := s.SignTx(ctx, tx, args.ChainId.ToInt())
+		if err != nil {
+			return nil, err
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return hexutil.Bytes(raw), nil
+
+	default:
+		return nil, fmt.Errorf("method %s not supported", method)
+	}
+}
@@ -0,0 +1,156 @@
+// Package signer signs transactions for Go-side chain writes, such as
+// SetMessage on HelloWorldL1/HelloWorldL2 or UpdateSocket on the
+// TaskAVSRegistrar. Keys can come from an encrypted JSON keystore, a private
+// key in the environment, or a remote signer speaking the web3signer
+// eth_signTransaction JSON-RPC API.
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions for a single account.
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// Signer backend types accepted by Config.Type.
+const (
+	TypeKeystore   = "keystore"
+	TypePrivateKey = "env"
+	TypeRemote     = "remote"
+)
+
+// Config selects and configures a signer backend.
+type Config struct {
+	// Type is one of TypeKeystore, TypePrivateKey or TypeRemote.
+	Type string
+
+	KeystorePath     string
+	KeystorePassword string
+
+	// PrivateKey is a hex encoded secp256k1 key, used by TypePrivateKey.
+	PrivateKey string
+
+	// RemoteURL and Address configure TypeRemote.
+	RemoteURL string
+	Address   string
+}
+
+// ConfigFromEnv reads the signer configuration from the environment:
+//
+//	SIGNER_TYPE                keystore, env or remote
+//	SIGNER_KEYSTORE_PATH       path to an encrypted JSON keystore
+//	SIGNER_KEYSTORE_PASSWORD   keystore password (or SIGNER_KEYSTORE_PASSWORD_FILE)
+//	SIGNER_PRIVATE_KEY         hex private key for the env signer
+//	SIGNER_REMOTE_URL          remote signer URL, e.g. http://localhost:9000
+//	SIGNER_ADDRESS             account to sign with on the remote signer
+func ConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		Type:             os.Getenv("SIGNER_TYPE"),
+		KeystorePath:     os.Getenv("SIGNER_KEYSTORE_PATH"),
+		KeystorePassword: os.Getenv("SIGNER_KEYSTORE_PASSWORD"),
+		PrivateKey:       os.Getenv("SIGNER_PRIVATE_KEY"),
+		RemoteURL:        os.Getenv("SIGNER_REMOTE_URL"),
+		Address:          os.Getenv("SIGNER_ADDRESS"),
+	}
+	if path := os.Getenv("SIGNER_KEYSTORE_PASSWORD_FILE"); path != "" && cfg.KeystorePassword == "" {
+		password, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SIGNER_KEYSTORE_PASSWORD_FILE: %w", err)
+		}
+		cfg.KeystorePassword = strings.TrimRight(string(password), "\r\n")
+	}
+	return cfg, nil
+}
+
+// New creates the signer described by cfg. If no type is set it is inferred
+// from the fields that are present.
+func New(cfg *Config) (Signer, error) {
+	typ := cfg.Type
+	if typ == "" {
+		switch {
+		case cfg.KeystorePath != "":
+			typ = TypeKeystore
+		case cfg.RemoteURL != "":
+			typ = TypeRemote
+		case cfg.PrivateKey != "":
+			typ = TypePrivateKey
+		default:
+			return nil, fmt.Errorf("no signer configured: set a keystore, private key or remote signer")
+		}
+	}
+
+	switch typ {
+	case TypeKeystore:
+		return NewKeystoreSigner(cfg.KeystorePath, cfg.KeystorePassword)
+	case TypePrivateKey:
+		return NewPrivateKeySigner(cfg.PrivateKey)
+	case TypeRemote:
+		if !common.IsHexAddress(cfg.Address) {
+			return nil, fmt.Errorf("remote signer requires a valid signer address, got %q", cfg.Address)
+		}
+		return NewRemoteSigner(cfg.RemoteURL, common.HexToAddress(cfg.Address)), nil
+	default:
+		return nil, fmt.Errorf("unknown signer type %q (expected %s, %s or %s)", typ, TypeKeystore, TypePrivateKey, TypeRemote)
+	}
+}
+
+// PrivateKeySigner signs with an in-memory private key.
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner creates a signer from a hex encoded private key, with or
+// without a 0x prefix.
+func NewPrivateKeySigner(hexKey string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return &PrivateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+// NewKeystoreSigner decrypts an encrypted JSON (Web3 Secret Storage) keystore.
+func NewKeystoreSigner(path string, password string) (*PrivateKeySigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	return &PrivateKeySigner{key: key.PrivateKey, address: key.Address}, nil
+}
+
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignHash signs a 32 byte hash, returning a 65 byte [R || S || V] signature
+// with V of 27 or 28 as expected by ECDSA.recover on-chain.
+func (s *PrivateKeySigner) SignHash(hash []byte) ([]byte, error) {
+	sig, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+func (s *PrivateKeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
@@ -0,0 +1,246 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Anvil's first default account
+const testPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+var testAddress = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+// fakeBackend implements the parts of Backend used by Transactor.
+type fakeBackend struct {
+	Backend
+	chainID *big.Int
+	nonce   uint64
+	tip     *big.Int
+	baseFee *big.Int
+
+	nonceCalls int
+}
+
+func (b *fakeBackend) ChainID(context.Context) (*big.Int, error) { return b.chainID, nil }
+
+func (b *fakeBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	b.nonceCalls++
+	return b.nonce, nil
+}
+
+func (b *fakeBackend) SuggestGasTipCap(context.Context) (*big.Int, error) { return b.tip, nil }
+
+func (b *fakeBackend) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: b.baseFee}, nil
+}
+
+func newTestTx(nonce uint64, chainID *big.Int) *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0x01, 0x02},
+	})
+}
+
+func checkSender(t *testing.T, tx *types.Transaction, chainID *big.Int, want common.Address) {
+	t.Helper()
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		t.Fatalf("failed to recover sender: %v", err)
+	}
+	if sender != want {
+		t.Fatalf("expected sender %s, got %s", want, sender)
+	}
+}
+
+func Test_PrivateKeySigner(t *testing.T) {
+	s, err := NewPrivateKeySigner(testPrivateKey)
+	if err != nil {
+		t.Fatalf("NewPrivateKeySigner failed: %v", err)
+	}
+	if s.Address() != testAddress {
+		t.Fatalf("expected address %s, got %s", testAddress, s.Address())
+	}
+
+	chainID := big.NewInt(31337)
+	signed, err := s.SignTx(context.Background(), newTestTx(0, chainID), chainID)
+	if err != nil {
+		t.Fatalf("SignTx failed: %v", err)
+	}
+	checkSender(t, signed, chainID, testAddress)
+
+	// Hash signatures use the 27/28 recovery ID expected on-chain
+	hash := crypto.Keccak256([]byte("message"))
+	sig, err := s.SignHash(hash)
+	if err != nil {
+		t.Fatalf("SignHash failed: %v", err)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Fatalf("expected V of 27 or 28, got %d", v)
+	}
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != testAddress {
+		t.Fatalf("hash signature does not recover to %s", testAddress)
+	}
+
+	if _, err := NewPrivateKeySigner("not-a-key"); err == nil {
+		t.Fatal("expected an error for an invalid key")
+	}
+}
+
+func Test_KeystoreSigner(t *testing.T) {
+	key, err := crypto.HexToECDSA(testPrivateKey[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := account.URL.Path
+
+	s, err := New(&Config{KeystorePath: path, KeystorePassword: "secret"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if s.Address() != testAddress {
+		t.Fatalf("expected address %s, got %s", testAddress, s.Address())
+	}
+
+	if _, err := NewKeystoreSigner(path, "wrong"); err == nil {
+		t.Fatal("expected an error for a wrong password")
+	}
+}
+
+func Test_RemoteSigner(t *testing.T) {
+	local, err := NewPrivateKeySigner(testPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewRemoteSignerHandler(local))
+	defer srv.Close()
+
+	s, err := New(&Config{Type: TypeRemote, RemoteURL: srv.URL, Address: testAddress.Hex()})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	chainID := big.NewInt(31337)
+	tx := newTestTx(7, chainID)
+	signed, err := s.SignTx(context.Background(), tx, chainID)
+	if err != nil {
+		t.Fatalf("SignTx failed: %v", err)
+	}
+	checkSender(t, signed, chainID, testAddress)
+	if !sameUnsigned(signed, tx) {
+		t.Fatal("signed transaction differs from the request")
+	}
+
+	// The remote side only knows its own account
+	other := NewRemoteSigner(srv.URL, common.HexToAddress("0x00000000000000000000000000000000000000bb"))
+	if _, err := other.SignTx(context.Background(), tx, chainID); err == nil {
+		t.Fatal("expected an error signing for an unknown account")
+	}
+}
+
+func Test_GasPolicy(t *testing.T) {
+	backend := &fakeBackend{tip: big.NewInt(3), baseFee: big.NewInt(100)}
+	ctx := context.Background()
+
+	tip, feeCap, err := GasPolicy{}.Fees(ctx, backend)
+	if err != nil {
+		t.Fatalf("Fees failed: %v", err)
+	}
+	if tip.Int64() != 3 || feeCap.Int64() != 203 {
+		t.Fatalf("expected tip 3 and fee cap 203, got %s and %s", tip, feeCap)
+	}
+
+	tip, feeCap, err = GasPolicy{TipCap: big.NewInt(5), BaseFeeMultiplier: 3}.Fees(ctx, backend)
+	if err != nil {
+		t.Fatalf("Fees failed: %v", err)
+	}
+	if tip.Int64() != 5 || feeCap.Int64() != 305 {
+		t.Fatalf("expected tip 5 and fee cap 305, got %s and %s", tip, feeCap)
+	}
+
+	tip, _, err = GasPolicy{MaxTipCap: big.NewInt(2)}.Fees(ctx, backend)
+	if err != nil || tip.Int64() != 2 {
+		t.Fatalf("expected tip capped at 2, got %v (%v)", tip, err)
+	}
+
+	if _, _, err := (GasPolicy{MaxFeeCap: big.NewInt(200)}).Fees(ctx, backend); err == nil {
+		t.Fatal("expected an error when the fee cap exceeds the maximum")
+	}
+
+	wei, err := ParseGwei("1.5")
+	if err != nil || wei.Int64() != 1_500_000_000 {
+		t.Fatalf("expected 1.5 gwei, got %v (%v)", wei, err)
+	}
+}
+
+func Test_Transactor(t *testing.T) {
+	backend := &fakeBackend{chainID: big.NewInt(31337), nonce: 4, tip: big.NewInt(1), baseFee: big.NewInt(10)}
+	s, err := NewPrivateKeySigner(testPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tr, err := NewTransactor(ctx, s, backend, GasPolicy{})
+	if err != nil {
+		t.Fatalf("NewTransactor failed: %v", err)
+	}
+
+	// Nonces are counted locally after the first lookup
+	for want := uint64(4); want < 7; want++ {
+		opts, err := tr.TransactOpts(ctx)
+		if err != nil {
+			t.Fatalf("TransactOpts failed: %v", err)
+		}
+		if opts.Nonce.Uint64() != want {
+			t.Fatalf("expected nonce %d, got %d", want, opts.Nonce)
+		}
+		if opts.GasFeeCap.Int64() != 21 || opts.GasTipCap.Int64() != 1 {
+			t.Fatalf("unexpected fees: tip %s, cap %s", opts.GasTipCap, opts.GasFeeCap)
+		}
+		signed, err := opts.Signer(opts.From, newTestTx(want, backend.chainID))
+		if err != nil {
+			t.Fatalf("opts.Signer failed: %v", err)
+		}
+		checkSender(t, signed, backend.chainID, testAddress)
+	}
+	if backend.nonceCalls != 1 {
+		t.Fatalf("expected 1 nonce lookup, got %d", backend.nonceCalls)
+	}
+
+	// A failed send releases the nonce
+	boom := errors.New("boom")
+	_, err = tr.Send(ctx, func(*bind.TransactOpts) (*types.Transaction, error) { return nil, boom })
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected the send error, got %v", err)
+	}
+	opts, err := tr.TransactOpts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.nonceCalls != 2 || opts.Nonce.Uint64() != 4 {
+		t.Fatalf("expected the nonce to be re-read after a failed send, got nonce %d after %d lookups", opts.Nonce, backend.nonceCalls)
+	}
+}
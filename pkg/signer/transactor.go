@@ -0,0 +1,121 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend is the chain access a Transactor needs. ethclient.Client
+// implements it.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// Transactor builds bind.TransactOpts for a Signer on one chain, assigning
+// nonces and EIP-1559 fees.
+type Transactor struct {
+	signer  Signer
+	backend Backend
+	chainID *big.Int
+	nonces  *NonceManager
+	policy  GasPolicy
+}
+
+// NewTransactor creates a Transactor for the chain behind backend.
+func NewTransactor(ctx context.Context, signer Signer, backend Backend, policy GasPolicy) (*Transactor, error) {
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chain ID: %w", err)
+	}
+	return &Transactor{
+		signer:  signer,
+		backend: backend,
+		chainID: chainID,
+		nonces:  NewNonceManager(backend),
+		policy:  policy,
+	}, nil
+}
+
+func (t *Transactor) Signer() Signer {
+	return t.signer
+}
+
+func (t *Transactor) ChainID() *big.Int {
+	return t.chainID
+}
+
+// TransactOpts returns options for a single transaction with its nonce and
+// fees filled in. Gas limits are left to the binding's estimate.
+func (t *Transactor) TransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	tipCap, feeCap, err := t.policy.Fees(ctx, t.backend)
+	if err != nil {
+		return nil, err
+	}
+
+	from := t.signer.Address()
+	nonce, err := t.nonces.Next(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch nonce for %s: %w", from, err)
+	}
+
+	return &bind.TransactOpts{
+		From:      from,
+		Nonce:     new(big.Int).SetUint64(nonce),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Context:   ctx,
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return t.signer.SignTx(ctx, tx, t.chainID)
+		},
+	}, nil
+}
+
+// Send calls a binding method with fresh TransactOpts. If the call fails, the
+// reserved nonce is released so the next transaction re-reads it.
+//
+//	tx, err := t.Send(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+//		return contract.SetMessage(opts, "hello")
+//	})
+func (t *Transactor) Send(ctx context.Context, fn func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	opts, err := t.TransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := fn(opts)
+	if err != nil {
+		t.nonces.Reset(opts.From)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ErrTransactionReverted is returned by SendAndWait when a mined
+// transaction failed.
+var ErrTransactionReverted = errors.New("transaction reverted")
+
+// SendAndWait is Send followed by waiting for the transaction to be mined.
+func (t *Transactor) SendAndWait(ctx context.Context, fn func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Receipt, error) {
+	tx, err := t.Send(ctx, fn)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := bind.WaitMined(ctx, t.backend, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for transaction %s: %w", tx.Hash(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("%w: %s", ErrTransactionReverted, tx.Hash())
+	}
+	return receipt, nil
+}
@@ -0,0 +1,218 @@
+// Package taskmetadata decodes the taskMetadata of an executor operator set's
+// TaskMailbox config as Performer settings, and watches it for changes. This
+// lets the AVS govern every operator's Performer on-chain.
+package taskmetadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/limiter"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Version is the schema version this Performer understands.
+const Version = 1
+
+// ErrHandlerDisabled is matched (via errors.Is) by every error returned when a
+// task is rejected because the AVS disabled its handler.
+var ErrHandlerDisabled = errors.New("handler disabled")
+
+// DisabledError is returned for tasks whose handler is disabled in the task
+// metadata. It carries the gRPC Unimplemented code.
+type DisabledError struct {
+	Handler string
+}
+
+func (e *DisabledError) Error() string {
+	return fmt.Sprintf("%s: handler %q is disabled by the AVS's task metadata", ErrHandlerDisabled, e.Handler)
+}
+
+func (e *DisabledError) Is(target error) bool {
+	return target == ErrHandlerDisabled
+}
+
+// GRPCStatus allows the gRPC server to map this error to codes.Unimplemented.
+func (e *DisabledError) GRPCStatus() *status.Status {
+	return status.New(codes.Unimplemented, e.Error())
+}
+
+// Handler is the on-chain configuration of one handler. Unset limits keep the
+// Performer's own configuration.
+type Handler struct {
+	Disabled      bool   `json:"disabled"`
+	MaxConcurrent *int   `json:"maxConcurrent"`
+	MaxQueued     *int   `json:"maxQueued"`
+	QueueTimeout  string `json:"queueTimeout"`
+}
+
+// Metadata is the decoded task metadata, a UTF-8 JSON object:
+//
+//	{
+//	  "version": 1,
+//	  "handlers": {
+//	    "default": {"maxConcurrent": 4, "maxQueued": 16, "queueTimeout": "5s"},
+//	    "heavy": {"disabled": true}
+//	  }
+//	}
+//
+// If handlers is not empty, handlers missing from it are disabled.
+type Metadata struct {
+	Version  int                `json:"version"`
+	Handlers map[string]Handler `json:"handlers"`
+}
+
+// Decode decodes task metadata. Empty metadata decodes to nil, which leaves
+// the Performer's own configuration in force.
+func Decode(data []byte) (*Metadata, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var m Metadata
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid task metadata: %w", err)
+	}
+	if m.Version != Version {
+		return nil, fmt.Errorf("unsupported task metadata version %d (this Performer supports version %d)", m.Version, Version)
+	}
+	for name, h := range m.Handlers {
+		if (h.MaxConcurrent != nil && *h.MaxConcurrent < 0) || (h.MaxQueued != nil && *h.MaxQueued < 0) {
+			return nil, fmt.Errorf("invalid task metadata: handler %q has a negative limit", name)
+		}
+		if h.QueueTimeout != "" {
+			if _, err := time.ParseDuration(h.QueueTimeout); err != nil {
+				return nil, fmt.Errorf("invalid task metadata: handler %q: invalid queueTimeout: %w", name, err)
+			}
+		}
+	}
+	return &m, nil
+}
+
+// Enabled reports whether the handler may run tasks. All handlers are enabled
+// without metadata.
+func (m *Metadata) Enabled(handler string) bool {
+	if m == nil || len(m.Handlers) == 0 {
+		return true
+	}
+	h, ok := m.Handlers[handler]
+	return ok && !h.Disabled
+}
+
+// Limits applies the metadata's handler limits on top of base, the
+// Performer's own limits.
+func (m *Metadata) Limits(base limiter.Config) (limiter.Limits, map[string]limiter.Limits) {
+	handlers := make(map[string]limiter.Limits, len(base.Handlers))
+	for name, l := range base.Handlers {
+		handlers[name] = l
+	}
+	if m == nil {
+		return base.Defaults, handlers
+	}
+
+	for name, h := range m.Handlers {
+		l, ok := handlers[name]
+		if !ok {
+			l = base.Defaults
+		}
+		if h.MaxConcurrent != nil {
+			l.MaxConcurrent = *h.MaxConcurrent
+		}
+		if h.MaxQueued != nil {
+			l.MaxQueued = *h.MaxQueued
+		}
+		if h.QueueTimeout != "" {
+			// Checked by Decode
+			l.QueueTimeout, _ = time.ParseDuration(h.QueueTimeout)
+		}
+		handlers[name] = l
+	}
+	return base.Defaults, handlers
+}
+
+// TaskConfigReader reads an executor operator set's task config.
+// *mailbox.TaskMailbox implements it.
+type TaskConfigReader interface {
+	GetExecutorOperatorSetTaskConfig(ctx context.Context, set mailbox.OperatorSet) (*mailbox.ExecutorOperatorSetTaskConfig, error)
+}
+
+// Watcher keeps the decoded task metadata of one operator set up to date.
+type Watcher struct {
+	reader TaskConfigReader
+	set    mailbox.OperatorSet
+
+	mu       sync.Mutex
+	raw      []byte
+	loaded   bool
+	metadata *Metadata
+}
+
+// NewWatcher creates a Watcher for set. It has no metadata until Load
+// succeeds.
+func NewWatcher(reader TaskConfigReader, set mailbox.OperatorSet) *Watcher {
+	return &Watcher{reader: reader, set: set}
+}
+
+// Current returns the last metadata that decoded successfully.
+func (w *Watcher) Current() *Metadata {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.metadata
+}
+
+// Load reads the task metadata and reports whether it changed. Metadata that
+// fails to decode is returned as an error and the current metadata is kept.
+func (w *Watcher) Load(ctx context.Context) (bool, error) {
+	cfg, err := w.reader.GetExecutorOperatorSetTaskConfig(ctx, w.set)
+	if err != nil {
+		return false, fmt.Errorf("failed to read the task config of operator set %s/%d: %w", w.set.Avs, w.set.Id, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.loaded && bytes.Equal(w.raw, cfg.TaskMetadata) {
+		return false, nil
+	}
+	// Remember bad metadata too, so that it is reported once per change
+	w.raw = cfg.TaskMetadata
+	w.loaded = true
+
+	metadata, err := Decode(cfg.TaskMetadata)
+	if err != nil {
+		return false, err
+	}
+	w.metadata = metadata
+	return true, nil
+}
+
+// Watch calls Load every interval until ctx is done. Changes are reported to
+// onChange and failures to onError.
+func (w *Watcher) Watch(ctx context.Context, interval time.Duration, onChange func(*Metadata), onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := w.Load(ctx)
+		if err != nil {
+			onError(err)
+			continue
+		}
+		if changed {
+			onChange(w.Current())
+		}
+	}
+}
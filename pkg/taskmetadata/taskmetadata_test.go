@@ -0,0 +1,111 @@
+package taskmetadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/limiter"
+	"github.com/Layr-Labs/hourglass-avs-template/pkg/mailbox"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeReader struct {
+	metadata []byte
+	err      error
+}
+
+func (f *fakeReader) GetExecutorOperatorSetTaskConfig(context.Context, mailbox.OperatorSet) (*mailbox.ExecutorOperatorSetTaskConfig, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &mailbox.ExecutorOperatorSetTaskConfig{TaskMetadata: f.metadata}, nil
+}
+
+func Test_Decode(t *testing.T) {
+	m, err := Decode([]byte(`{"version": 1, "handlers": {"default": {"maxConcurrent": 2}, "heavy": {"disabled": true}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Enabled("default") || m.Enabled("heavy") || m.Enabled("unlisted") {
+		t.Errorf("unexpected handlers %+v", m.Handlers)
+	}
+
+	if m, err := Decode(nil); m != nil || err != nil || !m.Enabled("default") {
+		t.Errorf("expected empty metadata to enable every handler, got %+v, %v", m, err)
+	}
+
+	for _, bad := range []string{
+		`{"handlers": {}}`,
+		`{"version": 2}`,
+		`{"version": 1, "handlerz": {}}`,
+		`{"version": 1, "handlers": {"default": {"maxQueued": -1}}}`,
+		`{"version": 1, "handlers": {"default": {"queueTimeout": "soon"}}}`,
+		"\x00\x01",
+	} {
+		if _, err := Decode([]byte(bad)); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func Test_Limits(t *testing.T) {
+	base := limiter.Config{
+		Defaults: limiter.Limits{MaxConcurrent: 8, MaxQueued: 8, QueueTimeout: time.Second},
+		Handlers: map[string]limiter.Limits{"heavy": {MaxConcurrent: 1}},
+	}
+	m, err := Decode([]byte(`{"version": 1, "handlers": {"default": {"maxConcurrent": 2, "queueTimeout": "5s"}, "heavy": {}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaults, handlers := m.Limits(base)
+	if defaults != base.Defaults {
+		t.Errorf("defaults = %+v, want %+v", defaults, base.Defaults)
+	}
+	if want := (limiter.Limits{MaxConcurrent: 2, MaxQueued: 8, QueueTimeout: 5 * time.Second}); handlers["default"] != want {
+		t.Errorf("default limits = %+v, want %+v", handlers["default"], want)
+	}
+	if handlers["heavy"] != base.Handlers["heavy"] {
+		t.Errorf("heavy limits = %+v, want the Performer's own", handlers["heavy"])
+	}
+}
+
+func Test_Watcher(t *testing.T) {
+	reader := &fakeReader{metadata: []byte(`{"version": 1, "handlers": {"default": {}}}`)}
+	w := NewWatcher(reader, mailbox.OperatorSet{Id: 1})
+	ctx := context.Background()
+
+	if changed, err := w.Load(ctx); !changed || err != nil {
+		t.Fatalf("Load = %t, %v", changed, err)
+	}
+	if changed, err := w.Load(ctx); changed || err != nil {
+		t.Fatalf("expected no change, got %t, %v", changed, err)
+	}
+
+	// Bad metadata is reported once and the last good metadata is kept
+	reader.metadata = []byte(`{"version": 99}`)
+	if _, err := w.Load(ctx); err == nil {
+		t.Fatal("expected an error for an unsupported version")
+	}
+	if _, err := w.Load(ctx); err != nil {
+		t.Fatalf("expected bad metadata to be reported once, got %v", err)
+	}
+	if !w.Current().Enabled("default") || w.Current().Enabled("heavy") {
+		t.Errorf("expected the last good metadata to be kept, got %+v", w.Current())
+	}
+
+	reader.err = errors.New("rpc down")
+	if _, err := w.Load(ctx); err == nil {
+		t.Fatal("expected the RPC error")
+	}
+}
+
+func Test_DisabledError(t *testing.T) {
+	err := error(&DisabledError{Handler: "heavy"})
+	if !errors.Is(err, ErrHandlerDisabled) || status.Code(err) != codes.Unimplemented {
+		t.Errorf("unexpected error %v (code %s)", err, status.Code(err))
+	}
+}